@@ -40,33 +40,44 @@ func main() {
 	}
 
 	source := string(contents)
-	logger := logger.New(source)
+	logger := logger.New(path, source)
 
-	scannerStart := time.Now()
-	tokens := scanner.New(source, logger).Scan()
-	scannerEnd := float64(time.Since(scannerStart)) / float64(time.Millisecond)
+	if *silent {
+		// Stream tokens straight out of the scanner instead of scanning the
+		// whole file up front, since there is no debug output here that
+		// needs the materialized Tokens slice anyway.
+		parserStart := time.Now()
+		parser.New(scanner.NewScannerSource(scanner.New(source, logger)), logger, parser.Default).ParseDocument()
+		parserEnd := float64(time.Since(parserStart)) / float64(time.Millisecond)
 
-	fmt.Printf("========== SCANNER: %f ms (%d tokens) ==========\n", scannerEnd, len(tokens))
+		fmt.Printf("========== PARSER: %f ms ==========\n", parserEnd)
 
-	if logger.Log() {
-		os.Exit(1)
-	}
+		if logger.Log() {
+			os.Exit(1)
+		}
+	} else {
+		scannerStart := time.Now()
+		tokens := scanner.New(source, logger).Scan()
+		scannerEnd := float64(time.Since(scannerStart)) / float64(time.Millisecond)
+
+		fmt.Printf("========== SCANNER: %f ms (%d tokens) ==========\n", scannerEnd, len(tokens))
+
+		if logger.Log() {
+			os.Exit(1)
+		}
 
-	if !*silent {
 		fmt.Println(tokens)
-	}
 
-	parserStart := time.Now()
-	expr := parser.New(tokens, logger).Parse()
-	parserEnd := float64(time.Since(parserStart)) / float64(time.Millisecond)
+		parserStart := time.Now()
+		expr := parser.New(scanner.NewTokenSource(tokens), logger, parser.Default).ParseDocument()
+		parserEnd := float64(time.Since(parserStart)) / float64(time.Millisecond)
 
-	fmt.Printf("========== PARSER: %f ms ==========\n", parserEnd)
+		fmt.Printf("========== PARSER: %f ms ==========\n", parserEnd)
 
-	if logger.Log() {
-		os.Exit(1)
-	}
+		if logger.Log() {
+			os.Exit(1)
+		}
 
-	if !*silent {
 		fmt.Println(expr)
 	}
 
@@ -0,0 +1,221 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/parser"
+)
+
+// callMemo is the cached outcome of calling a particular ExprFunc with a
+// particular argument tuple.
+type callMemo struct {
+	value parser.Expr
+	err   error
+}
+
+// evaluateCall resolves a call expression, either by invoking a builtin
+// registered under the callee's name or by evaluating an ExprFunc's body
+// against a fresh parameter scope. Results are memoized per (function,
+// argument tuple) in callCache, independent of which call site produced
+// them, so recursive calls to the same function with the same arguments
+// are not re-evaluated.
+func (evaluator *Evaluator) evaluateCall(call *parser.ExprCall) error {
+	data := evaluator.callDatas[call]
+
+	if data.evaluated {
+		return nil
+	}
+
+	data.evaluated = true
+
+	// Evaluate every argument to a concrete Go value up front. Builtins
+	// operate on those directly, and function calls use them to build both
+	// the parameter scope and the memoization key.
+	args := make([]any, len(call.Args))
+	argExprs := make([]parser.Expr, len(call.Args))
+
+	for index, arg := range call.Args {
+		argExpr, err := evaluator.unwrap(arg)
+		if err != nil {
+			return err
+		}
+
+		value, err := evaluator.resolve(argExpr)
+		if err != nil {
+			return err
+		}
+
+		argExprs[index] = argExpr
+		args[index] = value
+	}
+
+	// Builtins are looked up by name directly, since they are not values
+	// that live anywhere in the Expr tree.
+	if ident, ok := call.Callee.(*parser.ExprIdent); ok {
+		if builtin, ok := evaluator.builtins[ident.Name]; ok {
+			value, err := builtin(args)
+			if err != nil {
+				return &logger.Error{
+					Message: err.Error(),
+					Span:    call.Span(),
+				}
+			}
+
+			exprValue, err := fromGo(value, call.Span())
+			if err != nil {
+				return err
+			}
+
+			data.value = exprValue
+
+			return nil
+		}
+	}
+
+	calleeExpr, err := evaluator.unwrap(call.Callee)
+	if err != nil {
+		return err
+	}
+
+	fn, ok := calleeExpr.(*parser.ExprFunc)
+	if !ok {
+		return &logger.Error{
+			Message: "Expect function for call.",
+			Span:    call.Callee.Span(),
+		}
+	}
+
+	if len(fn.Params) != len(call.Args) {
+		return &logger.Error{
+			Message: "Wrong number of arguments.",
+			Span:    call.Span(),
+		}
+	}
+
+	key := callKey(args)
+
+	cache, ok := evaluator.callCache[fn]
+	if !ok {
+		cache = map[string]*callMemo{}
+		evaluator.callCache[fn] = cache
+	}
+
+	if memo, ok := cache[key]; ok {
+		data.value = memo.value
+		return memo.err
+	}
+
+	env := newEnv(evaluator.funcDatas[fn].env)
+
+	for index, param := range fn.Params {
+		env = env.Set(param.Name, argExprs[index])
+	}
+
+	value, err := evaluator.callFunc(fn, env)
+	cache[key] = &callMemo{value: value, err: err}
+
+	if err != nil {
+		return err
+	}
+
+	data.value = value
+
+	return nil
+}
+
+// callFunc evaluates fn's body against env. It binds and evaluates the body
+// in a fresh set of node-keyed data maps rather than the Evaluator's usual
+// ones, since the body's AST nodes are shared across every call and each
+// call needs its own identData/letData/etc. scoped to its own arguments.
+func (evaluator *Evaluator) callFunc(fn *parser.ExprFunc, env *Env) (parser.Expr, error) {
+	savedRefDatas := evaluator.refDatas
+	savedIdentDatas := evaluator.identDatas
+	savedLetDatas := evaluator.letDatas
+	savedUnaryDatas := evaluator.unaryDatas
+	savedBinaryDatas := evaluator.binaryDatas
+	savedInterpDatas := evaluator.interpDatas
+	savedArrayDatas := evaluator.arrayDatas
+	savedTableDatas := evaluator.tableDatas
+	savedFuncDatas := evaluator.funcDatas
+	savedCallDatas := evaluator.callDatas
+
+	evaluator.refDatas = map[*parser.ExprRef]*refData{}
+	evaluator.identDatas = map[*parser.ExprIdent]*identData{}
+	evaluator.letDatas = map[*parser.ExprLet]*letData{}
+	evaluator.unaryDatas = map[*parser.ExprUnary]*opData{}
+	evaluator.binaryDatas = map[*parser.ExprBinary]*opData{}
+	evaluator.interpDatas = map[*parser.ExprInterp]*opData{}
+	evaluator.arrayDatas = map[*parser.ExprArray]*arrayData{}
+	evaluator.tableDatas = map[*parser.ExprTable]*tableData{}
+	evaluator.funcDatas = map[*parser.ExprFunc]*funcData{}
+	evaluator.callDatas = map[*parser.ExprCall]*callData{}
+
+	defer func() {
+		// A func literal nested in fn's body, such as a closure fn's body
+		// returns, is bound fresh into this call's temporary funcDatas. If
+		// that closure escapes the call, it still needs its env to resolve
+		// when it is eventually called itself, so merge what this call
+		// bound into the outer, permanent funcDatas instead of discarding
+		// it with the rest of this call's scoped state.
+		for inner, data := range evaluator.funcDatas {
+			savedFuncDatas[inner] = data
+		}
+
+		evaluator.refDatas = savedRefDatas
+		evaluator.identDatas = savedIdentDatas
+		evaluator.letDatas = savedLetDatas
+		evaluator.unaryDatas = savedUnaryDatas
+		evaluator.binaryDatas = savedBinaryDatas
+		evaluator.interpDatas = savedInterpDatas
+		evaluator.arrayDatas = savedArrayDatas
+		evaluator.tableDatas = savedTableDatas
+		evaluator.funcDatas = savedFuncDatas
+		evaluator.callDatas = savedCallDatas
+	}()
+
+	// Function bodies have no enclosing table to fall back to: an
+	// unresolved identifier inside one is simply undefined.
+	evaluator.bind(fn.Body, nil, env)
+
+	if err := evaluator.evaluate(fn.Body); err != nil {
+		return nil, err
+	}
+
+	return evaluator.unwrap(fn.Body)
+}
+
+// callKey turns a resolved argument tuple into a string suitable for use as
+// a memoization key.
+func callKey(args []any) string {
+	return fmt.Sprintf("%#v", args)
+}
+
+// fromGo converts a builtin's Go return value back into an Expr so it can
+// flow through the rest of the evaluator like any other value. Only scalar
+// types are supported, since the builtins this is meant for (len, contains,
+// min, max, string helpers) all return scalars.
+func fromGo(value any, span logger.Span) (parser.Expr, error) {
+	switch current := value.(type) {
+	case int64:
+		return &parser.ExprInt{Value: current, ValueSpan: span}, nil
+
+	case int:
+		return &parser.ExprInt{Value: int64(current), ValueSpan: span}, nil
+
+	case float64:
+		return &parser.ExprFloat{Value: current, ValueSpan: span}, nil
+
+	case string:
+		return &parser.ExprStr{Value: current, ValueSpan: span}, nil
+
+	case bool:
+		return &parser.ExprBool{Value: current, ValueSpan: span}, nil
+
+	default:
+		return nil, &logger.Error{
+			Message: "Builtin returned an unsupported value.",
+			Span:    span,
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package evaluator
+
+import "testing"
+
+func TestFuncCall(t *testing.T) {
+	value := mustEvaluate(t, "x: let add = func(a, b) a + b in add(1, 2)")
+
+	table := value.(map[string]any)
+
+	if table["x"] != int64(3) {
+		t.Errorf("expect x to be 3, got %v", table["x"])
+	}
+}
+
+func TestFuncCallClosureCapturesOuterBinding(t *testing.T) {
+	value := mustEvaluate(t, "x: let make = func(a) func(b) a + b in let add5 = make(5) in add5(2)")
+
+	table := value.(map[string]any)
+
+	if table["x"] != int64(7) {
+		t.Errorf("expect x to be 7, got %v", table["x"])
+	}
+}
+
+func TestFuncCallWrongArgCount(t *testing.T) {
+	expr, log := mustParse(t, "x: let add = func(a, b) a + b in add(1)")
+
+	New(expr, log).Evaluate()
+
+	if !log.Log() {
+		t.Fatalf("expect a wrong-arity call to report an error")
+	}
+}
+
+func TestFuncCallMemoizesByArguments(t *testing.T) {
+	expr, log := mustParse(t, "x: let id = func(a) a in [id(1), id(1), id(2)]")
+
+	evaluator := New(expr, log)
+	value := evaluator.Evaluate()
+
+	if log.Log() {
+		t.Fatalf("unexpected errors")
+	}
+
+	table := value.(map[string]any)
+	items := table["x"].([]any)
+
+	if items[0] != int64(1) || items[1] != int64(1) || items[2] != int64(2) {
+		t.Errorf("expect [1, 1, 2], got %v", items)
+	}
+}
+
+func TestBuiltinCall(t *testing.T) {
+	expr, log := mustParse(t, "x: double(21)")
+
+	evaluator := New(expr, log)
+	evaluator.RegisterBuiltin("double", func(args []any) (any, error) {
+		return args[0].(int64) * 2, nil
+	})
+
+	value := evaluator.Evaluate()
+
+	if log.Log() {
+		t.Fatalf("unexpected errors")
+	}
+
+	table := value.(map[string]any)
+
+	if table["x"] != int64(42) {
+		t.Errorf("expect x to be 42, got %v", table["x"])
+	}
+}
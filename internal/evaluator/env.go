@@ -0,0 +1,57 @@
+package evaluator
+
+import "github.com/HereIsKevin/edible/internal/parser"
+
+// Env is a lexical scope: a flat map of names to the expression bound to
+// each, plus a pointer to the enclosing scope. Evaluator.bind pushes a new
+// Env whenever it enters a let or a table body, so identifiers resolve
+// through the nearest binding first and only fall back to the current
+// table.
+type Env struct {
+	bindings map[string]parser.Expr
+	parent   *Env
+}
+
+// newEnv creates a scope nested inside parent. parent may be nil for the
+// outermost scope.
+func newEnv(parent *Env) *Env {
+	return &Env{
+		bindings: map[string]parser.Expr{},
+		parent:   parent,
+	}
+}
+
+// Get looks up name, walking outward through enclosing scopes. If local is
+// set, only this Env's own bindings are consulted.
+func (env *Env) Get(name string, local bool) (parser.Expr, bool) {
+	if env == nil {
+		return nil, false
+	}
+
+	if value, ok := env.bindings[name]; ok {
+		return value, true
+	}
+
+	if local {
+		return nil, false
+	}
+
+	return env.parent.Get(name, local)
+}
+
+// Set binds name to value in this scope and returns the scope to use for
+// anything bound afterward. If name is already bound here, the existing
+// scope is left untouched and a fresh child scope is returned instead, so a
+// repeated binding shadows rather than silently overwrites the first one.
+func (env *Env) Set(name string, value parser.Expr) *Env {
+	if _, ok := env.bindings[name]; ok {
+		child := newEnv(env)
+		child.bindings[name] = value
+
+		return child
+	}
+
+	env.bindings[name] = value
+
+	return env
+}
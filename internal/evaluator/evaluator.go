@@ -11,6 +11,37 @@ type refData struct {
 	evaluated bool
 }
 
+type identData struct {
+	env   *Env
+	table parser.Expr
+
+	value     parser.Expr
+	evaluated bool
+
+	// resolving guards against a let binding that transitively refers to
+	// itself; evaluateIdent sets it for the duration of resolving this
+	// identifier and reports an error if it is already set on reentry.
+	resolving bool
+}
+
+type letData struct {
+	env       *Env
+	value     parser.Expr
+	evaluated bool
+}
+
+// funcData records the closure env an ExprFunc was defined in. The body
+// itself is bound fresh for every call instead of once here, since each
+// call needs its own parameter scope; see callFunc in call.go.
+type funcData struct {
+	env *Env
+}
+
+type callData struct {
+	value     parser.Expr
+	evaluated bool
+}
+
 type opData struct {
 	value     parser.Expr
 	evaluated bool
@@ -36,10 +67,23 @@ type Evaluator struct {
 	logger *logger.Logger
 
 	refDatas    map[*parser.ExprRef]*refData
+	identDatas  map[*parser.ExprIdent]*identData
+	letDatas    map[*parser.ExprLet]*letData
 	unaryDatas  map[*parser.ExprUnary]*opData
 	binaryDatas map[*parser.ExprBinary]*opData
+	interpDatas map[*parser.ExprInterp]*opData
 	arrayDatas  map[*parser.ExprArray]*arrayData
 	tableDatas  map[*parser.ExprTable]*tableData
+	funcDatas   map[*parser.ExprFunc]*funcData
+	callDatas   map[*parser.ExprCall]*callData
+
+	// callCache memoizes a function's result per argument tuple, keyed by
+	// callKey, so calling the same pure function with the same arguments
+	// more than once (as happens naturally with recursion) does not
+	// re-evaluate its body.
+	callCache map[*parser.ExprFunc]map[string]*callMemo
+
+	builtins map[string]func(args []any) (any, error)
 }
 
 func New(expr parser.Expr, logger *logger.Logger) *Evaluator {
@@ -48,22 +92,45 @@ func New(expr parser.Expr, logger *logger.Logger) *Evaluator {
 		logger: logger,
 
 		refDatas:    map[*parser.ExprRef]*refData{},
+		identDatas:  map[*parser.ExprIdent]*identData{},
+		letDatas:    map[*parser.ExprLet]*letData{},
 		unaryDatas:  map[*parser.ExprUnary]*opData{},
 		binaryDatas: map[*parser.ExprBinary]*opData{},
+		interpDatas: map[*parser.ExprInterp]*opData{},
 		arrayDatas:  map[*parser.ExprArray]*arrayData{},
 		tableDatas:  map[*parser.ExprTable]*tableData{},
+		funcDatas:   map[*parser.ExprFunc]*funcData{},
+		callDatas:   map[*parser.ExprCall]*callData{},
+
+		callCache: map[*parser.ExprFunc]map[string]*callMemo{},
+		builtins:  map[string]func(args []any) (any, error){},
 	}
 }
 
+// RegisterBuiltin exposes a host function under name, so that e.g. a call
+// expression `len($.items)` can be answered without len needing to exist
+// anywhere in the Expr tree. Builtins run eagerly against already-resolved
+// Go values rather than lazily against Expr nodes.
+func (evaluator *Evaluator) RegisterBuiltin(name string, fn func(args []any) (any, error)) {
+	evaluator.builtins[name] = fn
+}
+
 func (evaluator *Evaluator) Evaluate() any {
+	root := newEnv(nil)
+
 	if _, ok := evaluator.expr.(*parser.ExprTable); ok {
-		evaluator.bind(evaluator.expr, evaluator.expr)
+		evaluator.bind(evaluator.expr, evaluator.expr, root)
 	} else {
-		evaluator.bind(evaluator.expr, nil)
+		evaluator.bind(evaluator.expr, nil, root)
 	}
 
 	if err := evaluator.evaluate(evaluator.expr); err != nil {
+		// Do not go on to resolve: a node that failed partway through
+		// evaluate is left marked evaluated with no value to show for it
+		// (see itemData, letData, ...), so resolve would just find nil
+		// where it expects an Expr.
 		evaluator.logger.AddError(err)
+		return nil
 	}
 
 	value, err := evaluator.resolve(evaluator.expr)
@@ -74,11 +141,51 @@ func (evaluator *Evaluator) Evaluate() any {
 	return value
 }
 
-func (evaluator *Evaluator) bind(expr parser.Expr, parent parser.Expr) {
+// bind walks expr, recording evaluation state for every node ahead of time.
+// parent is the nearest enclosing table, used both as the root for relative
+// references and as the fallback scope for identifiers that no Env binds.
+// env is the lexical scope identifiers resolve against first; it grows a
+// new child whenever bind enters a let or a table body.
+func (evaluator *Evaluator) bind(expr parser.Expr, parent parser.Expr, env *Env) {
 	switch current := expr.(type) {
-	case *parser.ExprStr, *parser.ExprBool, *parser.ExprInt, *parser.ExprFloat:
+	case *parser.ExprStr, *parser.ExprBool, *parser.ExprInt, *parser.ExprFloat, *parser.ExprBad:
 		// Skip literals.
 
+	case *parser.ExprIdent:
+		evaluator.identDatas[current] = &identData{
+			env:   env,
+			table: parent,
+		}
+
+	case *parser.ExprLet:
+		scope := newEnv(env)
+
+		for _, binding := range current.Bindings {
+			scope = scope.Set(binding.Name, binding.Value)
+		}
+
+		evaluator.letDatas[current] = &letData{
+			env: scope,
+		}
+
+		for _, binding := range current.Bindings {
+			evaluator.bind(binding.Value, parent, scope)
+		}
+
+		evaluator.bind(current.Body, parent, scope)
+
+	case *parser.ExprFunc:
+		evaluator.funcDatas[current] = &funcData{env: env}
+
+	case *parser.ExprCall:
+		evaluator.callDatas[current] = &callData{}
+
+		evaluator.bind(current.Callee, parent, env)
+
+		for _, arg := range current.Args {
+			evaluator.bind(arg, parent, env)
+		}
+
 	case *parser.ExprRef:
 		root := parent
 		if current.Modifier == parser.RefAbsolute {
@@ -92,7 +199,7 @@ func (evaluator *Evaluator) bind(expr parser.Expr, parent parser.Expr) {
 		}
 
 		for _, key := range current.Keys {
-			evaluator.bind(key, parent)
+			evaluator.bind(key, parent, env)
 		}
 
 	case *parser.ExprUnary:
@@ -101,7 +208,7 @@ func (evaluator *Evaluator) bind(expr parser.Expr, parent parser.Expr) {
 			evaluated: false,
 		}
 
-		evaluator.bind(current.Right, parent)
+		evaluator.bind(current.Right, parent, env)
 
 	case *parser.ExprBinary:
 		evaluator.binaryDatas[current] = &opData{
@@ -109,8 +216,20 @@ func (evaluator *Evaluator) bind(expr parser.Expr, parent parser.Expr) {
 			evaluated: false,
 		}
 
-		evaluator.bind(current.Left, parent)
-		evaluator.bind(current.Right, parent)
+		evaluator.bind(current.Left, parent, env)
+		evaluator.bind(current.Right, parent, env)
+
+	case *parser.ExprInterp:
+		evaluator.interpDatas[current] = &opData{
+			value:     nil,
+			evaluated: false,
+		}
+
+		for _, part := range current.Parts {
+			if part.Value != nil {
+				evaluator.bind(part.Value, parent, env)
+			}
+		}
 
 	case *parser.ExprArray:
 		evaluator.arrayDatas[current] = &arrayData{
@@ -119,31 +238,53 @@ func (evaluator *Evaluator) bind(expr parser.Expr, parent parser.Expr) {
 		}
 
 		for _, item := range current.Items {
-			evaluator.bind(item, parent)
+			evaluator.bind(item, parent, env)
 		}
 
 	case *parser.ExprTable:
+		tableEnv := newEnv(env)
+
 		evaluator.tableDatas[current] = &tableData{
 			items:     map[string]*itemData{},
 			evaluated: false,
 		}
 
-		if current.Parent != nil {
-			evaluator.bind(current.Parent, current)
-		}
-
 		for _, item := range current.Items {
-			evaluator.bind(item.Key, current)
-			evaluator.bind(item.Value, current)
+			// A `key < parent: value` item's parent is resolved in the
+			// table's own enclosing scope, the same as the table itself.
+			if item.Parent != nil {
+				evaluator.bind(item.Parent, parent, env)
+			}
+
+			evaluator.bind(item.Key, current, tableEnv)
+			evaluator.bind(item.Value, current, tableEnv)
 		}
 	}
 }
 
 func (evaluator *Evaluator) evaluate(expr parser.Expr) error {
 	switch current := expr.(type) {
-	case *parser.ExprStr, *parser.ExprBool, *parser.ExprInt, *parser.ExprFloat:
+	case *parser.ExprStr, *parser.ExprBool, *parser.ExprInt, *parser.ExprFloat, *parser.ExprBad:
 		// Skip literals.
 
+	case *parser.ExprIdent:
+		if err := evaluator.evaluateIdent(current); err != nil {
+			return err
+		}
+
+	case *parser.ExprLet:
+		if err := evaluator.evaluateLet(current); err != nil {
+			return err
+		}
+
+	case *parser.ExprFunc:
+		// Nothing to evaluate eagerly; the body only runs once called.
+
+	case *parser.ExprCall:
+		if err := evaluator.evaluateCall(current); err != nil {
+			return err
+		}
+
 	case *parser.ExprRef:
 		if err := evaluator.evaluateRef(current); err != nil {
 			return err
@@ -159,6 +300,11 @@ func (evaluator *Evaluator) evaluate(expr parser.Expr) error {
 			return err
 		}
 
+	case *parser.ExprInterp:
+		if err := evaluator.evaluateInterp(current); err != nil {
+			return err
+		}
+
 	case *parser.ExprArray:
 		if err := evaluator.evaluateArray(current); err != nil {
 			return err
@@ -173,6 +319,94 @@ func (evaluator *Evaluator) evaluate(expr parser.Expr) error {
 	return nil
 }
 
+func (evaluator *Evaluator) evaluateIdent(ident *parser.ExprIdent) error {
+	data := evaluator.identDatas[ident]
+
+	// Exit if identifier is already evaluated.
+	if data.evaluated {
+		return nil
+	}
+
+	// A reentrant call while still resolving this same identifier means a
+	// let binding transitively refers to itself.
+	if data.resolving {
+		return &logger.Error{
+			Message: "Cyclic reference in let binding.",
+			Span:    ident.NameSpan,
+		}
+	}
+
+	data.resolving = true
+	defer func() { data.resolving = false }()
+
+	// Resolve through lexical scope first.
+	if bound, ok := data.env.Get(ident.Name, false); ok {
+		value, err := evaluator.unwrap(bound)
+		if err != nil {
+			return err
+		}
+
+		data.value = value
+		data.evaluated = true
+
+		return nil
+	}
+
+	// Fall back to the enclosing table's own keys.
+	if table, ok := data.table.(*parser.ExprTable); ok {
+		if err := evaluator.evaluateTableKeys(table); err != nil {
+			return err
+		}
+
+		item, ok := evaluator.tableDatas[table].items[ident.Name]
+		if !ok {
+			return &logger.Error{
+				Message: "Undefined identifier.",
+				Span:    ident.NameSpan,
+			}
+		}
+
+		if err := evaluator.evaluateItem(item); err != nil {
+			return err
+		}
+
+		value, err := evaluator.unwrap(item.value)
+		if err != nil {
+			return err
+		}
+
+		data.value = value
+		data.evaluated = true
+
+		return nil
+	}
+
+	return &logger.Error{
+		Message: "Undefined identifier.",
+		Span:    ident.NameSpan,
+	}
+}
+
+func (evaluator *Evaluator) evaluateLet(let *parser.ExprLet) error {
+	data := evaluator.letDatas[let]
+
+	// Exit if already evaluated.
+	if data.evaluated {
+		return nil
+	}
+
+	data.evaluated = true
+
+	value, err := evaluator.unwrap(let.Body)
+	if err != nil {
+		return err
+	}
+
+	data.value = value
+
+	return nil
+}
+
 func (evaluator *Evaluator) evaluateRef(ref *parser.ExprRef) error {
 	data := evaluator.refDatas[ref]
 
@@ -207,7 +441,7 @@ func (evaluator *Evaluator) evaluateRef(ref *parser.ExprRef) error {
 			if !ok {
 				return &logger.Error{
 					Message: "Expect integer for array index.",
-					Pos:     rawKey.Pos(),
+					Span:    rawKey.Span(),
 				}
 			}
 
@@ -217,10 +451,10 @@ func (evaluator *Evaluator) evaluateRef(ref *parser.ExprRef) error {
 			items := evaluator.arrayDatas[current].items
 
 			// Make sure index is in bounds.
-			if int64(len(items)) <= index.Value {
+			if int64(len(items)) <= index.Value || index.Value < 0 {
 				return &logger.Error{
 					Message: "Index out of bounds.",
-					Pos:     rawKey.Pos(),
+					Span:    rawKey.Span(),
 				}
 			}
 
@@ -240,7 +474,7 @@ func (evaluator *Evaluator) evaluateRef(ref *parser.ExprRef) error {
 			if !ok {
 				return &logger.Error{
 					Message: "Expect string for table key.",
-					Pos:     rawKey.Pos(),
+					Span:    rawKey.Span(),
 				}
 			}
 
@@ -254,7 +488,7 @@ func (evaluator *Evaluator) evaluateRef(ref *parser.ExprRef) error {
 			if !ok {
 				return &logger.Error{
 					Message: "Key not found.",
-					Pos:     rawKey.Pos(),
+					Span:    rawKey.Span(),
 				}
 			}
 
@@ -265,6 +499,12 @@ func (evaluator *Evaluator) evaluateRef(ref *parser.ExprRef) error {
 
 			// Repeat with the item value.
 			expr = item.value
+
+		default:
+			return &logger.Error{
+				Message: "Expect array or table for key path.",
+				Span:    rawKey.Span(),
+			}
 		}
 	}
 
@@ -287,6 +527,8 @@ func (evaluator *Evaluator) evaluateUnary(unary *parser.ExprUnary) error {
 		return nil
 	}
 
+	data.evaluated = true
+
 	// Unwrap expression.
 	expr, err := evaluator.unwrap(unary.Right)
 	if err != nil {
@@ -298,20 +540,20 @@ func (evaluator *Evaluator) evaluateUnary(unary *parser.ExprUnary) error {
 		switch current := expr.(type) {
 		case *parser.ExprInt:
 			data.value = &parser.ExprInt{
-				Value:    current.Value,
-				Position: unary.Right.Pos(),
+				Value:     current.Value,
+				ValueSpan: unary.Right.Span(),
 			}
 
 		case *parser.ExprFloat:
 			data.value = &parser.ExprFloat{
-				Value:    current.Value,
-				Position: unary.Right.Pos(),
+				Value:     current.Value,
+				ValueSpan: unary.Right.Span(),
 			}
 
 		default:
 			return &logger.Error{
 				Message: "Expect integer or float.",
-				Pos:     unary.Right.Pos(),
+				Span:    unary.Right.Span(),
 			}
 		}
 
@@ -319,22 +561,31 @@ func (evaluator *Evaluator) evaluateUnary(unary *parser.ExprUnary) error {
 		switch current := expr.(type) {
 		case *parser.ExprInt:
 			data.value = &parser.ExprInt{
-				Value:    -current.Value,
-				Position: unary.Right.Pos(),
+				Value:     -current.Value,
+				ValueSpan: unary.Right.Span(),
 			}
 
 		case *parser.ExprFloat:
 			data.value = &parser.ExprFloat{
-				Value:    -current.Value,
-				Position: unary.Right.Pos(),
+				Value:     -current.Value,
+				ValueSpan: unary.Right.Span(),
 			}
 
 		default:
 			return &logger.Error{
 				Message: "Expect integer or float.",
-				Pos:     unary.Right.Pos(),
+				Span:    unary.Right.Span(),
 			}
 		}
+
+	default:
+		// UnaryGte, UnaryLte, and UnaryMatch are schema constraint
+		// predicates; they describe a value rather than computing one, and
+		// only mean something inside internal/schema.Unify.
+		return &logger.Error{
+			Message: "Schema constraint used outside of a schema.",
+			Span:    unary.Span(),
+		}
 	}
 
 	return nil
@@ -348,6 +599,8 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 		return nil
 	}
 
+	data.evaluated = true
+
 	// Unwrap left.
 	leftExpr, err := evaluator.unwrap(binary.Left)
 	if err != nil {
@@ -360,10 +613,9 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 		return err
 	}
 
-	position := logger.Pos{
-		Start: binary.Left.Pos().Start,
-		End:   binary.Right.Pos().End,
-		Line:  binary.Left.Pos().Line,
+	span := logger.Span{
+		Start: binary.Left.Span().Start,
+		End:   binary.Right.Span().End,
 	}
 
 	switch binary.Op {
@@ -373,20 +625,20 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprInt{
-					Value:    left.Value + right.Value,
-					Position: position,
+					Value:     left.Value + right.Value,
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    float64(left.Value) + right.Value,
-					Position: position,
+					Value:     float64(left.Value) + right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
@@ -394,27 +646,27 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value + float64(right.Value),
-					Position: position,
+					Value:     left.Value + float64(right.Value),
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value + right.Value,
-					Position: position,
+					Value:     left.Value + right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
 		default:
 			return &logger.Error{
 				Message: "Expect integer or float.",
-				Pos:     binary.Left.Pos(),
+				Span:    binary.Left.Span(),
 			}
 		}
 
@@ -424,20 +676,20 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprInt{
-					Value:    left.Value - right.Value,
-					Position: position,
+					Value:     left.Value - right.Value,
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    float64(left.Value) - right.Value,
-					Position: position,
+					Value:     float64(left.Value) - right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
@@ -445,27 +697,27 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value - float64(right.Value),
-					Position: position,
+					Value:     left.Value - float64(right.Value),
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value - right.Value,
-					Position: position,
+					Value:     left.Value - right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
 		default:
 			return &logger.Error{
 				Message: "Expect integer or float.",
-				Pos:     binary.Left.Pos(),
+				Span:    binary.Left.Span(),
 			}
 		}
 
@@ -475,20 +727,20 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprInt{
-					Value:    left.Value * right.Value,
-					Position: position,
+					Value:     left.Value * right.Value,
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    float64(left.Value) * right.Value,
-					Position: position,
+					Value:     float64(left.Value) * right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
@@ -496,27 +748,27 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value * float64(right.Value),
-					Position: position,
+					Value:     left.Value * float64(right.Value),
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value * right.Value,
-					Position: position,
+					Value:     left.Value * right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
 		default:
 			return &logger.Error{
 				Message: "Expect integer or float.",
-				Pos:     binary.Left.Pos(),
+				Span:    binary.Left.Span(),
 			}
 		}
 
@@ -526,20 +778,20 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprInt{
-					Value:    left.Value / right.Value,
-					Position: position,
+					Value:     left.Value / right.Value,
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    float64(left.Value) / right.Value,
-					Position: position,
+					Value:     float64(left.Value) / right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
@@ -547,34 +799,160 @@ func (evaluator *Evaluator) evaluateBinary(binary *parser.ExprBinary) error {
 			switch right := rightExpr.(type) {
 			case *parser.ExprInt:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value / float64(right.Value),
-					Position: position,
+					Value:     left.Value / float64(right.Value),
+					ValueSpan: span,
 				}
 
 			case *parser.ExprFloat:
 				data.value = &parser.ExprFloat{
-					Value:    left.Value / right.Value,
-					Position: position,
+					Value:     left.Value / right.Value,
+					ValueSpan: span,
 				}
 
 			default:
 				return &logger.Error{
 					Message: "Expect integer or float.",
-					Pos:     binary.Right.Pos(),
+					Span:    binary.Right.Span(),
 				}
 			}
 
 		default:
 			return &logger.Error{
 				Message: "Expect integer or float.",
-				Pos:     binary.Left.Pos(),
+				Span:    binary.Left.Span(),
+			}
+		}
+
+	case parser.BinaryEq, parser.BinaryNeq:
+		equal, err := exprEqual(leftExpr, rightExpr)
+		if err != nil {
+			return err
+		}
+
+		if binary.Op == parser.BinaryNeq {
+			equal = !equal
+		}
+
+		data.value = &parser.ExprBool{Value: equal, ValueSpan: span}
+
+	case parser.BinaryLt, parser.BinaryLte, parser.BinaryGt, parser.BinaryGte:
+		left, ok := numericValue(leftExpr)
+		if !ok {
+			return &logger.Error{
+				Message: "Expect integer or float.",
+				Span:    binary.Left.Span(),
+			}
+		}
+
+		right, ok := numericValue(rightExpr)
+		if !ok {
+			return &logger.Error{
+				Message: "Expect integer or float.",
+				Span:    binary.Right.Span(),
 			}
 		}
+
+		var result bool
+
+		switch binary.Op {
+		case parser.BinaryLt:
+			result = left < right
+		case parser.BinaryLte:
+			result = left <= right
+		case parser.BinaryGt:
+			result = left > right
+		case parser.BinaryGte:
+			result = left >= right
+		}
+
+		data.value = &parser.ExprBool{Value: result, ValueSpan: span}
+
+	case parser.BinaryAnd, parser.BinaryOr:
+		left, ok := leftExpr.(*parser.ExprBool)
+		if !ok {
+			return &logger.Error{
+				Message: "Expect boolean.",
+				Span:    binary.Left.Span(),
+			}
+		}
+
+		right, ok := rightExpr.(*parser.ExprBool)
+		if !ok {
+			return &logger.Error{
+				Message: "Expect boolean.",
+				Span:    binary.Right.Span(),
+			}
+		}
+
+		if binary.Op == parser.BinaryAnd {
+			data.value = &parser.ExprBool{Value: left.Value && right.Value, ValueSpan: span}
+		} else {
+			data.value = &parser.ExprBool{Value: left.Value || right.Value, ValueSpan: span}
+		}
+
+	default:
+		// BinaryUnify and BinaryDisjoin are schema operators; they compose
+		// constraints rather than values, and only mean something inside
+		// internal/schema.Unify.
+		return &logger.Error{
+			Message: "Schema operator used outside of a schema.",
+			Span:    span,
+		}
 	}
 
 	return nil
 }
 
+// exprEqual compares two concrete values for equality. Numbers compare
+// across Int and Float; every other pair must share the same concrete type.
+func exprEqual(left, right parser.Expr) (bool, error) {
+	switch leftValue := left.(type) {
+	case *parser.ExprInt:
+		switch rightValue := right.(type) {
+		case *parser.ExprInt:
+			return leftValue.Value == rightValue.Value, nil
+		case *parser.ExprFloat:
+			return float64(leftValue.Value) == rightValue.Value, nil
+		}
+
+	case *parser.ExprFloat:
+		switch rightValue := right.(type) {
+		case *parser.ExprInt:
+			return leftValue.Value == float64(rightValue.Value), nil
+		case *parser.ExprFloat:
+			return leftValue.Value == rightValue.Value, nil
+		}
+
+	case *parser.ExprStr:
+		if rightValue, ok := right.(*parser.ExprStr); ok {
+			return leftValue.Value == rightValue.Value, nil
+		}
+
+	case *parser.ExprBool:
+		if rightValue, ok := right.(*parser.ExprBool); ok {
+			return leftValue.Value == rightValue.Value, nil
+		}
+	}
+
+	return false, &logger.Error{
+		Message: "Cannot compare values of different types.",
+		Span:    logger.Span{Start: left.Span().Start, End: right.Span().End},
+	}
+}
+
+// numericValue extracts a float64 out of an Int or Float expression, for
+// operators that treat the two as interchangeable.
+func numericValue(expr parser.Expr) (float64, bool) {
+	switch current := expr.(type) {
+	case *parser.ExprInt:
+		return float64(current.Value), true
+	case *parser.ExprFloat:
+		return current.Value, true
+	default:
+		return 0, false
+	}
+}
+
 func (evaluator *Evaluator) evaluateArrayIndices(array *parser.ExprArray) {
 	data := evaluator.arrayDatas[array]
 
@@ -619,28 +997,69 @@ func (evaluator *Evaluator) evaluateTableKeys(table *parser.ExprTable) error {
 	data.evaluated = true
 
 	for _, item := range table.Items {
+		// Keys are always parsed as strings (or ExprBad on a parse error).
+		key, ok := item.Key.(*parser.ExprStr)
+		if !ok {
+			return &logger.Error{
+				Message: "Expect string for table key.",
+				Span:    item.Key.Span(),
+			}
+		}
+
 		// Check for duplicate keys.
-		if _, ok := data.items[item.Key.Value]; ok {
+		if _, ok := data.items[key.Value]; ok {
 			return &logger.Error{
 				Message: "Duplicate key in table.",
-				Pos:     item.Key.Pos(),
+				Span:    item.Key.Span(),
 			}
 		}
 
 		// Create entry in table.
-		data.items[item.Key.Value] = &itemData{
+		data.items[key.Value] = &itemData{
 			value:     item.Value,
 			evaluated: false,
 		}
+
+		// A `key < parent: value` item inherits any keys its own value
+		// doesn't define from parent.
+		if item.Parent != nil {
+			if err := evaluator.inheritItem(item); err != nil {
+				return err
+			}
+		}
 	}
 
-	// Exit if there is no parent.
-	if table.Parent == nil {
-		return nil
+	return nil
+}
+
+// inheritItem implements `key < parent: value` inheritance: any key parent
+// defines that value's own table doesn't is merged into value, the same way
+// CUE embedding works. value must itself be a table, since a parent only
+// has somewhere to merge into if value is one.
+func (evaluator *Evaluator) inheritItem(item *parser.TableItem) error {
+	// Unwrap value.
+	valueExpr, err := evaluator.unwrap(item.Value)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the value is a table.
+	value, ok := valueExpr.(*parser.ExprTable)
+	if !ok {
+		return &logger.Error{
+			Message: "Expect table for value with parent.",
+			Span:    item.Value.Span(),
+		}
+	}
+
+	// Make sure value's own keys are collected before merging parent's in,
+	// so value's own keys still win over parent's.
+	if err := evaluator.evaluateTableKeys(value); err != nil {
+		return err
 	}
 
 	// Unwrap parent.
-	parentExpr, err := evaluator.unwrap(table.Parent)
+	parentExpr, err := evaluator.unwrap(item.Parent)
 	if err != nil {
 		return err
 	}
@@ -650,7 +1069,7 @@ func (evaluator *Evaluator) evaluateTableKeys(table *parser.ExprTable) error {
 	if !ok {
 		return &logger.Error{
 			Message: "Expect table for parent.",
-			Pos:     table.Parent.Pos(),
+			Span:    item.Parent.Span(),
 		}
 	}
 
@@ -659,12 +1078,13 @@ func (evaluator *Evaluator) evaluateTableKeys(table *parser.ExprTable) error {
 		return err
 	}
 
+	valueData := evaluator.tableDatas[value]
 	parentData := evaluator.tableDatas[parent]
 
-	for key, item := range parentData.items {
+	for key, parentItem := range parentData.items {
 		// Merge item from parent if key is not already there.
-		if _, ok := data.items[key]; !ok {
-			data.items[key] = item
+		if _, ok := valueData.items[key]; !ok {
+			valueData.items[key] = parentItem
 		}
 	}
 
@@ -718,11 +1138,29 @@ func (evaluator *Evaluator) unwrap(expr parser.Expr) (parser.Expr, error) {
 		*parser.ExprInt,
 		*parser.ExprFloat,
 		*parser.ExprArray,
-		*parser.ExprTable:
+		*parser.ExprTable,
+		*parser.ExprFunc,
+		*parser.ExprBad:
 
-		// Exit on concrete values.
+		// Exit on concrete values. ExprFunc is a real, storable value (it
+		// can be passed around and called), just not one resolve can turn
+		// into output.
 		return expr, nil
 
+	case *parser.ExprIdent:
+		if err := evaluator.evaluateIdent(current); err != nil {
+			return nil, err
+		}
+
+		return evaluator.identDatas[current].value, nil
+
+	case *parser.ExprLet:
+		if err := evaluator.evaluateLet(current); err != nil {
+			return nil, err
+		}
+
+		return evaluator.letDatas[current].value, nil
+
 	case *parser.ExprRef:
 		if err := evaluator.evaluateRef(current); err != nil {
 			return nil, err
@@ -743,12 +1181,26 @@ func (evaluator *Evaluator) unwrap(expr parser.Expr) (parser.Expr, error) {
 		}
 
 		return evaluator.binaryDatas[current].value, nil
+
+	case *parser.ExprInterp:
+		if err := evaluator.evaluateInterp(current); err != nil {
+			return nil, err
+		}
+
+		return evaluator.interpDatas[current].value, nil
+
+	case *parser.ExprCall:
+		if err := evaluator.evaluateCall(current); err != nil {
+			return nil, err
+		}
+
+		return evaluator.callDatas[current].value, nil
 	}
 
 	// Expression is invalid if it somehow does not match.
 	return nil, &logger.Error{
 		Message: "Invalid expression.",
-		Pos:     expr.Pos(),
+		Span:    expr.Span(),
 	}
 }
 
@@ -772,6 +1224,11 @@ func (evaluator *Evaluator) resolve(expr parser.Expr) (any, error) {
 		return current.Value, nil
 
 	case *parser.ExprArray:
+		// unwrap only returns the array as-is rather than running
+		// evaluateArray, so a reached-through-unwrap-only array (e.g. the
+		// body of a let) would otherwise see no indices here at all.
+		evaluator.evaluateArrayIndices(current)
+
 		items := []any{}
 
 		for _, item := range evaluator.arrayDatas[current].items {
@@ -786,6 +1243,12 @@ func (evaluator *Evaluator) resolve(expr parser.Expr) (any, error) {
 		return items, nil
 
 	case *parser.ExprTable:
+		// Same reasoning as ExprArray above: make sure keys exist before
+		// reading them, since unwrap does not evaluate tables either.
+		if err := evaluator.evaluateTableKeys(current); err != nil {
+			return nil, err
+		}
+
 		items := map[string]any{}
 
 		for key, item := range evaluator.tableDatas[current].items {
@@ -798,10 +1261,16 @@ func (evaluator *Evaluator) resolve(expr parser.Expr) (any, error) {
 		}
 
 		return items, nil
+
+	case *parser.ExprFunc:
+		return nil, &logger.Error{
+			Message: "Cannot resolve a function to output.",
+			Span:    current.Span(),
+		}
 	}
 
 	return nil, &logger.Error{
 		Message: "Invalid expression.",
-		Pos:     expr.Pos(),
+		Span:    expr.Span(),
 	}
 }
@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/parser"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// mustParse parses source as a whole document, failing t immediately if
+// scanning or parsing reports any error.
+func mustParse(t *testing.T, source string) (parser.Expr, *logger.Logger) {
+	t.Helper()
+
+	log := logger.New("<test>", source)
+	tokens := scanner.New(source, log).Scan()
+
+	if log.Log() {
+		t.Fatalf("scan %q: unexpected errors", source)
+	}
+
+	expr := parser.New(scanner.NewTokenSource(tokens), log, parser.Default).ParseDocument()
+
+	if log.Log() {
+		t.Fatalf("parse %q: unexpected errors", source)
+	}
+
+	return expr, log
+}
+
+// mustEvaluate parses source as a whole document and evaluates it, failing
+// t immediately if scanning, parsing, or evaluating reports any error.
+func mustEvaluate(t *testing.T, source string) any {
+	t.Helper()
+
+	expr, log := mustParse(t, source)
+
+	value := New(expr, log).Evaluate()
+
+	if log.Log() {
+		t.Fatalf("evaluate %q: unexpected errors", source)
+	}
+
+	return value
+}
+
+func TestLetBinding(t *testing.T) {
+	value := mustEvaluate(t, "x: let a = 1, b = a + 1 in b")
+
+	table, ok := value.(map[string]any)
+	if !ok {
+		t.Fatalf("expect table, got %T", value)
+	}
+
+	if table["x"] != int64(2) {
+		t.Errorf("expect x to be 2, got %v", table["x"])
+	}
+}
+
+func TestLetBindingReferencesEarlierBinding(t *testing.T) {
+	value := mustEvaluate(t, "x: let a = 1, b = a, c = b in c")
+
+	table := value.(map[string]any)
+
+	if table["x"] != int64(1) {
+		t.Errorf("expect x to be 1, got %v", table["x"])
+	}
+}
+
+func TestLetBindingCycleIsAnError(t *testing.T) {
+	expr, log := mustParse(t, "x: let a = a in a")
+
+	New(expr, log).Evaluate()
+
+	if !log.Log() {
+		t.Fatalf("expect a cyclic let binding to report an error")
+	}
+}
+
+func TestTableInheritsParentKeys(t *testing.T) {
+	value := mustEvaluate(t, "base:\n  a: 1\n  b: 2\nchild < base:\n  b: 3")
+
+	table := value.(map[string]any)
+
+	child, ok := table["child"].(map[string]any)
+	if !ok {
+		t.Fatalf("expect child to be a table, got %T", table["child"])
+	}
+
+	if child["a"] != int64(1) {
+		t.Errorf("expect child.a inherited from base to be 1, got %v", child["a"])
+	}
+
+	if child["b"] != int64(3) {
+		t.Errorf("expect child.b to keep its own value 3, got %v", child["b"])
+	}
+}
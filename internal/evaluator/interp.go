@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HereIsKevin/edible/internal/parser"
+)
+
+// evaluateInterp resolves each embedded expression in interp, coerces it to
+// a string, and concatenates the result with interp's literal chunks.
+func (evaluator *Evaluator) evaluateInterp(interp *parser.ExprInterp) error {
+	data := evaluator.interpDatas[interp]
+
+	if data.evaluated {
+		return nil
+	}
+
+	data.evaluated = true
+
+	var builder strings.Builder
+
+	for _, part := range interp.Parts {
+		if part.Value == nil {
+			builder.WriteString(part.Literal)
+			continue
+		}
+
+		value, err := evaluator.resolve(part.Value)
+		if err != nil {
+			return err
+		}
+
+		builder.WriteString(coerceString(value))
+	}
+
+	data.value = &parser.ExprStr{
+		Value:     builder.String(),
+		ValueSpan: interp.Span(),
+	}
+
+	return nil
+}
+
+// coerceString converts a resolved value into the text spliced into an
+// interpolated string. Scalars use their natural text form; arrays and
+// tables fall back to renderValue's canonical, JSON-like rendering.
+func coerceString(value any) string {
+	switch current := value.(type) {
+	case string:
+		return current
+
+	case bool:
+		return strconv.FormatBool(current)
+
+	case int64:
+		return strconv.FormatInt(current, 10)
+
+	case float64:
+		return strconv.FormatFloat(current, 'g', -1, 64)
+
+	case []any, map[string]any:
+		return renderValue(current)
+
+	default:
+		return fmt.Sprintf("%v", current)
+	}
+}
+
+// renderValue renders an array or table (and, recursively, anything nested
+// inside one) as a canonical, deterministic string. Table keys are sorted
+// since Go map iteration order is not stable, and nested strings are quoted
+// to keep the structure unambiguous.
+func renderValue(value any) string {
+	switch current := value.(type) {
+	case string:
+		return strconv.Quote(current)
+
+	case []any:
+		items := make([]string, len(current))
+
+		for index, item := range current {
+			items[index] = renderValue(item)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+
+	case map[string]any:
+		keys := make([]string, 0, len(current))
+
+		for key := range current {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		items := make([]string, len(keys))
+
+		for index, key := range keys {
+			items[index] = key + ": " + renderValue(current[key])
+		}
+
+		return "{" + strings.Join(items, ", ") + "}"
+
+	default:
+		return coerceString(current)
+	}
+}
@@ -0,0 +1,43 @@
+package evaluator
+
+import "testing"
+
+func TestInterpScalar(t *testing.T) {
+	value := mustEvaluate(t, `x: "total: \(1 + 1)"`)
+
+	table := value.(map[string]any)
+
+	if table["x"] != "total: 2" {
+		t.Errorf(`expect "total: 2", got %v`, table["x"])
+	}
+}
+
+func TestInterpMultipleParts(t *testing.T) {
+	value := mustEvaluate(t, `x: let name = "world" in "hello, \(name)!"`)
+
+	table := value.(map[string]any)
+
+	if table["x"] != "hello, world!" {
+		t.Errorf(`expect "hello, world!", got %v`, table["x"])
+	}
+}
+
+func TestInterpArray(t *testing.T) {
+	value := mustEvaluate(t, `x: "items: \([1, 2])"`)
+
+	table := value.(map[string]any)
+
+	if table["x"] != "items: [1, 2]" {
+		t.Errorf(`expect "items: [1, 2]", got %v`, table["x"])
+	}
+}
+
+func TestInterpTableSortsKeys(t *testing.T) {
+	value := mustEvaluate(t, `x: "value: \({b: 2, a: 1})"`)
+
+	table := value.(map[string]any)
+
+	if table["x"] != "value: {a: 1, b: 2}" {
+		t.Errorf(`expect keys sorted in the rendered table, got %v`, table["x"])
+	}
+}
@@ -0,0 +1,25 @@
+package evaluator
+
+import "github.com/HereIsKevin/edible/internal/query"
+
+// Query runs a JSONPath-like path, such as "$.users[*].name", against the
+// tree Evaluate produces and returns every match.
+func (evaluator *Evaluator) Query(path string) ([]any, error) {
+	segments, err := query.Compile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.Run(evaluator.Evaluate(), segments)
+}
+
+// QueryIter is like Query, but returns an iterator that produces matches
+// one at a time instead of collecting them all up front.
+func (evaluator *Evaluator) QueryIter(path string) (*query.Iter, error) {
+	segments, err := query.Compile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.NewIter(evaluator.Evaluate(), segments), nil
+}
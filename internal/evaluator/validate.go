@@ -0,0 +1,87 @@
+package evaluator
+
+import (
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/parser"
+	"github.com/HereIsKevin/edible/internal/schema"
+)
+
+// Validate checks the tree Evaluate produces against schemaExpr (typically
+// parsed with schema.Compile) and reports a logger.Error pointing at
+// whichever constraint in schemaExpr the value failed.
+func (evaluator *Evaluator) Validate(schemaExpr parser.Expr) error {
+	value := evaluator.Evaluate()
+
+	// A resolved value has no source position of its own, so every
+	// synthesized node below is stamped with the root expression's span
+	// instead of leaving it at Span{}: schema.Unify's errors report
+	// Span: value.Span() for many violations (satisfiesType,
+	// satisfiesPredicate, unifyScalars, ...), and a zero Span is
+	// logger.NoPos, which File.Position can only turn into the zero
+	// Position, with no filename, line, or column.
+	valueExpr, err := toExpr(value, evaluator.expr.Span())
+	if err != nil {
+		return err
+	}
+
+	_, err = schema.Unify(valueExpr, schemaExpr)
+
+	return err
+}
+
+// toExpr converts a resolved Go value back into an Expr so it can be
+// compared against a schema with schema.Unify, stamping every synthesized
+// node with span, since a resolved value no longer carries a source
+// position of its own.
+func toExpr(value any, span logger.Span) (parser.Expr, error) {
+	switch current := value.(type) {
+	case nil:
+		return &parser.ExprIdent{Name: "null", NameSpan: span}, nil
+
+	case string:
+		return &parser.ExprStr{Value: current, ValueSpan: span}, nil
+
+	case bool:
+		return &parser.ExprBool{Value: current, ValueSpan: span}, nil
+
+	case int64:
+		return &parser.ExprInt{Value: current, ValueSpan: span}, nil
+
+	case float64:
+		return &parser.ExprFloat{Value: current, ValueSpan: span}, nil
+
+	case []any:
+		items := make([]parser.Expr, len(current))
+
+		for index, item := range current {
+			itemExpr, err := toExpr(item, span)
+			if err != nil {
+				return nil, err
+			}
+
+			items[index] = itemExpr
+		}
+
+		return &parser.ExprArray{OpenSpan: span, Items: items, CloseSpan: span}, nil
+
+	case map[string]any:
+		items := make([]*parser.TableItem, 0, len(current))
+
+		for key, item := range current {
+			itemExpr, err := toExpr(item, span)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, &parser.TableItem{
+				Key:   &parser.ExprStr{Value: key, ValueSpan: span},
+				Value: itemExpr,
+			})
+		}
+
+		return &parser.ExprTable{OpenSpan: span, Items: items, CloseSpan: span}, nil
+
+	default:
+		return nil, &logger.Error{Message: "Cannot validate this value.", Span: span}
+	}
+}
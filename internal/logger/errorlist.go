@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ErrorList is a sortable, printable collection of diagnostics, modeled on
+// go/scanner.ErrorList. It keeps a reference to the File its Errors' Spans
+// belong to, since ordering and printing both need a human Position
+// (filename, line, column) rather than a raw offset.
+type ErrorList struct {
+	file   *File
+	errors []Error
+}
+
+// Len implements sort.Interface.
+func (list *ErrorList) Len() int {
+	return len(list.errors)
+}
+
+// Less implements sort.Interface, ordering by (filename, line, column,
+// message) so diagnostics print in a deterministic, file-reading order
+// regardless of which pass reported them first.
+func (list *ErrorList) Less(i, j int) bool {
+	a := list.file.Position(list.errors[i].Span.Start)
+	b := list.file.Position(list.errors[j].Span.Start)
+
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+
+	if a.Column != b.Column {
+		return a.Column < b.Column
+	}
+
+	return list.errors[i].Message < list.errors[j].Message
+}
+
+// Swap implements sort.Interface.
+func (list *ErrorList) Swap(i, j int) {
+	list.errors[i], list.errors[j] = list.errors[j], list.errors[i]
+}
+
+// Sort orders list in place by (filename, line, column, message).
+func (list *ErrorList) Sort() {
+	sort.Sort(list)
+}
+
+// Err returns list as an error, or nil if it is empty, the same nil-means-ok
+// convention errors.Join uses.
+func (list *ErrorList) Err() error {
+	if len(list.errors) == 0 {
+		return nil
+	}
+
+	return list
+}
+
+// Error reports the first diagnostic in list, plus a count of the rest, so
+// a caller that only wants a single error string still sees how many
+// problems there were.
+func (list *ErrorList) Error() string {
+	if len(list.errors) == 0 {
+		return "no errors"
+	}
+
+	first := fmt.Sprintf("%s: %s", list.file.Position(list.errors[0].Span.Start), list.errors[0].Message)
+
+	if len(list.errors) == 1 {
+		return first
+	}
+
+	return fmt.Sprintf("%s (and %d more errors)", first, len(list.errors)-1)
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestErrorListSortOrdersByPosition(t *testing.T) {
+	file := NewFileSet().AddFile("a.edb", 20)
+	file.AddLine(file.Base() + 10)
+
+	list := ErrorList{
+		file: file,
+		errors: []Error{
+			{Message: "z", Span: Span{Start: file.Base() + 12}}, // line 2, col 3
+			{Message: "a", Span: Span{Start: file.Base()}},      // line 1, col 1
+			{Message: "m", Span: Span{Start: file.Base() + 2}},  // line 1, col 3
+		},
+	}
+
+	list.Sort()
+
+	if !sort.IsSorted(&list) {
+		t.Fatalf("expect list to report itself sorted after Sort")
+	}
+
+	if list.errors[0].Message != "a" || list.errors[1].Message != "m" || list.errors[2].Message != "z" {
+		t.Errorf("expect [a, m, z] in file-reading order, got %v", list.errors)
+	}
+}
+
+func TestErrorListLessBreaksTiesByMessage(t *testing.T) {
+	file := NewFileSet().AddFile("a.edb", 20)
+
+	list := ErrorList{
+		file: file,
+		errors: []Error{
+			{Message: "b", Span: Span{Start: file.Base()}},
+			{Message: "a", Span: Span{Start: file.Base()}},
+		},
+	}
+
+	list.Sort()
+
+	if list.errors[0].Message != "a" || list.errors[1].Message != "b" {
+		t.Errorf("expect ties at the same position broken by message, got %v", list.errors)
+	}
+}
+
+func TestErrorListErrReturnsNilWhenEmpty(t *testing.T) {
+	list := ErrorList{file: NewFileSet().AddFile("a.edb", 1)}
+
+	if err := list.Err(); err != nil {
+		t.Errorf("expect Err() to be nil for an empty list, got %v", err)
+	}
+}
+
+func TestErrorListErrReturnsItselfWhenNonEmpty(t *testing.T) {
+	file := NewFileSet().AddFile("a.edb", 1)
+	list := ErrorList{file: file, errors: []Error{{Message: "boom", Span: Span{Start: file.Base()}}}}
+
+	err := list.Err()
+	if err == nil {
+		t.Fatalf("expect a non-nil error")
+	}
+
+	if _, ok := err.(*ErrorList); !ok {
+		t.Errorf("expect Err() to return the *ErrorList itself, got %T", err)
+	}
+}
+
+func TestErrorListErrorReportsCountOfExtraErrors(t *testing.T) {
+	file := NewFileSet().AddFile("a.edb", 1)
+	list := ErrorList{file: file, errors: []Error{
+		{Message: "first", Span: Span{Start: file.Base()}},
+		{Message: "second", Span: Span{Start: file.Base()}},
+	}}
+
+	got := list.Error()
+
+	if want := "a.edb:1:1: first (and 1 more errors)"; got != want {
+		t.Errorf("expect %q, got %q", want, got)
+	}
+}
+
+func TestErrorListErrorSingleEntryHasNoCount(t *testing.T) {
+	file := NewFileSet().AddFile("a.edb", 1)
+	list := ErrorList{file: file, errors: []Error{{Message: "only", Span: Span{Start: file.Base()}}}}
+
+	if got, want := list.Error(), "a.edb:1:1: only"; got != want {
+		t.Errorf("expect %q, got %q", want, got)
+	}
+}
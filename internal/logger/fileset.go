@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Pos is a compact, opaque offset into a FileSet, modeled on go/token.Pos.
+// It carries no filename or line information by itself; File.Position
+// reconstructs that on demand from the file's line-start table.
+type Pos int32
+
+// NoPos is the zero value for Pos; it does not belong to any File.
+const NoPos Pos = 0
+
+// Span is a half-open range [Start, End) within a FileSet.
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+// Position is the human-readable form of a Pos, reconstructed on demand.
+type Position struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+// String formats position the way compilers traditionally do, e.g.
+// "path/to/file.edb:12:5", omitting the filename when it is empty.
+func (position Position) String() string {
+	if position.Filename == "" {
+		return fmt.Sprintf("%d:%d", position.Line, position.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", position.Filename, position.Line, position.Column)
+}
+
+// File records the offsets of a single source file within a FileSet: its
+// base offset and a sorted slice of line-start offsets. The scanner appends
+// to the line table every time it consumes a '\n', so Position never has to
+// rescan the source.
+type File struct {
+	name  string
+	base  Pos
+	size  int
+	lines []Pos
+}
+
+func newFile(name string, base Pos, size int) *File {
+	return &File{
+		name:  name,
+		base:  base,
+		size:  size,
+		lines: []Pos{base},
+	}
+}
+
+// Base returns the offset of the first byte of the file within its FileSet.
+func (file *File) Base() Pos {
+	return file.base
+}
+
+// Size returns the length of the file in bytes.
+func (file *File) Size() int {
+	return file.size
+}
+
+// AddLine records that a new line begins at offset, which must be the
+// absolute (FileSet-relative) position immediately after the newline
+// character. Offsets that don't advance past the last recorded line are
+// ignored, so callers don't need to track whether a given '\n' was already
+// recorded.
+func (file *File) AddLine(offset Pos) {
+	if n := len(file.lines); n == 0 || file.lines[n-1] < offset {
+		file.lines = append(file.lines, offset)
+	}
+}
+
+// Position reconstructs {Filename, Line, Column} for pos via a binary
+// search over the line table. It returns the zero Position for pos
+// belonging to no recorded line (NoPos, or any other position before the
+// file's first line), the same way FileSet.Position does for a pos
+// belonging to no File, rather than trusting every caller to never pass
+// one.
+func (file *File) Position(pos Pos) Position {
+	line := sort.Search(len(file.lines), func(i int) bool {
+		return file.lines[i] > pos
+	})
+
+	if line == 0 {
+		return Position{}
+	}
+
+	return Position{
+		Filename: file.name,
+		Line:     line,
+		Column:   int(pos-file.lines[line-1]) + 1,
+	}
+}
+
+// PosRange reconstructs the start and end Position for a span, which is
+// convenient for diagnostics that want to report both ends of a range.
+func (file *File) PosRange(start, end Pos) (Position, Position) {
+	return file.Position(start), file.Position(end)
+}
+
+// FileSet tracks the offsets of zero or more Files so a single Pos value
+// can be resolved back to the file, line, and column it came from, modeled
+// on go/token.FileSet.
+type FileSet struct {
+	base  Pos
+	files []*File
+}
+
+// NewFileSet creates an empty FileSet. Base offsets start at 1 so that
+// NoPos (0) never collides with a real position.
+func NewFileSet() *FileSet {
+	return &FileSet{base: 1}
+}
+
+// AddFile reserves size+1 bytes of offset space for a new file (the extra
+// byte accounts for a position just past the last byte, e.g. EOF) and
+// returns the File that tracks it.
+func (set *FileSet) AddFile(filename string, size int) *File {
+	file := newFile(filename, set.base, size)
+	set.files = append(set.files, file)
+	set.base += Pos(size) + 1
+
+	return file
+}
+
+// File returns the File containing pos, or nil if pos belongs to none of
+// the files in the set.
+func (set *FileSet) File(pos Pos) *File {
+	for _, file := range set.files {
+		if pos >= file.base && pos <= file.base+Pos(file.size) {
+			return file
+		}
+	}
+
+	return nil
+}
+
+// Position resolves pos via whichever File contains it. It returns the
+// zero Position if pos belongs to no File in the set.
+func (set *FileSet) Position(pos Pos) Position {
+	if file := set.File(pos); file != nil {
+		return file.Position(pos)
+	}
+
+	return Position{}
+}
@@ -0,0 +1,97 @@
+package logger
+
+import "testing"
+
+func TestFilePositionTracksLines(t *testing.T) {
+	set := NewFileSet()
+	file := set.AddFile("a.edb", 10)
+
+	file.AddLine(file.Base() + 4)
+	file.AddLine(file.Base() + 8)
+
+	position := file.Position(file.Base())
+	if position.Line != 1 || position.Column != 1 {
+		t.Errorf("expect line 1 column 1 at the base offset, got %+v", position)
+	}
+
+	position = file.Position(file.Base() + 5)
+	if position.Line != 2 || position.Column != 2 {
+		t.Errorf("expect line 2 column 2, got %+v", position)
+	}
+
+	position = file.Position(file.Base() + 9)
+	if position.Line != 3 || position.Column != 2 {
+		t.Errorf("expect line 3 column 2, got %+v", position)
+	}
+}
+
+func TestFilePositionReturnsZeroForNoPos(t *testing.T) {
+	set := NewFileSet()
+	file := set.AddFile("a.edb", 10)
+	file.AddLine(file.Base() + 4)
+
+	if position := file.Position(NoPos); position != (Position{}) {
+		t.Errorf("expect the zero Position for NoPos, got %+v", position)
+	}
+}
+
+func TestFileAddLineIgnoresNonIncreasingOffsets(t *testing.T) {
+	set := NewFileSet()
+	file := set.AddFile("a.edb", 10)
+
+	file.AddLine(file.Base() + 4)
+	file.AddLine(file.Base() + 4)
+	file.AddLine(file.Base() + 2)
+
+	// Both repeat and out-of-order AddLine calls should have been ignored,
+	// leaving exactly one extra line start beyond the implicit first line.
+	if position := file.Position(file.Base() + 5); position.Line != 2 {
+		t.Errorf("expect exactly one extra line recorded, got line %d", position.Line)
+	}
+}
+
+func TestFileSetFileFindsContainingFile(t *testing.T) {
+	set := NewFileSet()
+	a := set.AddFile("a.edb", 5)
+	b := set.AddFile("b.edb", 5)
+
+	if found := set.File(a.Base() + 2); found != a {
+		t.Errorf("expect a position inside a.edb to resolve to a")
+	}
+
+	if found := set.File(b.Base() + 2); found != b {
+		t.Errorf("expect a position inside b.edb to resolve to b")
+	}
+}
+
+func TestFileSetFileReturnsNilOutsideEveryFile(t *testing.T) {
+	set := NewFileSet()
+	set.AddFile("a.edb", 5)
+
+	if found := set.File(NoPos); found != nil {
+		t.Errorf("expect NoPos to belong to no File, got %v", found)
+	}
+}
+
+func TestFileSetPositionReturnsZeroOutsideEveryFile(t *testing.T) {
+	set := NewFileSet()
+	set.AddFile("a.edb", 5)
+
+	if position := set.Position(NoPos); position != (Position{}) {
+		t.Errorf("expect the zero Position outside every File, got %+v", position)
+	}
+}
+
+func TestPositionStringOmitsEmptyFilename(t *testing.T) {
+	position := Position{Line: 3, Column: 7}
+
+	if got, want := position.String(), "3:7"; got != want {
+		t.Errorf("expect %q, got %q", want, got)
+	}
+
+	position.Filename = "a.edb"
+
+	if got, want := position.String(), "a.edb:3:7"; got != want {
+		t.Errorf("expect %q, got %q", want, got)
+	}
+}
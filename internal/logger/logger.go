@@ -2,15 +2,9 @@ package logger
 
 import "fmt"
 
-type Pos struct {
-	Start int
-	End   int
-	Line  int
-}
-
 type Error struct {
 	Message string
-	Pos     Pos
+	Span    Span
 }
 
 func (err *Error) Error() string {
@@ -19,46 +13,65 @@ func (err *Error) Error() string {
 
 type Logger struct {
 	source string
-	errors []Error
+	file   *File
+	errors ErrorList
 }
 
-func New(source string) *Logger {
+func New(filename, source string) *Logger {
+	file := NewFileSet().AddFile(filename, len(source))
+
 	return &Logger{
 		source: source,
-		errors: []Error{},
+		file:   file,
+		errors: ErrorList{file: file},
 	}
 }
 
+// File returns the File backing this Logger's source, so callers that need
+// to mint Pos values for the same text (the scanner, in particular) share
+// its offsets.
+func (logger *Logger) File() *File {
+	return logger.file
+}
+
 func (logger *Logger) Log() bool {
-	if len(logger.errors) == 0 {
+	if logger.errors.Len() == 0 {
 		// Did not have errors.
 		return false
 	}
 
+	// Sort so diagnostics print in file-reading order no matter which pass
+	// reported them first.
+	logger.errors.Sort()
+
 	// Log every error.
-	for _, err := range logger.errors {
-		fmt.Printf(
-			"[Line %d] Error at '%s': %s\n",
-			err.Pos.Line,
-			logger.source[err.Pos.Start:err.Pos.End],
-			err.Message,
-		)
+	for _, err := range logger.errors.errors {
+		position := logger.file.Position(err.Span.Start)
+		fmt.Printf("%s: %s\n", position, err.Message)
 	}
 
 	// Clear errors.
-	logger.errors = nil
+	logger.errors.errors = nil
 
 	// Had errors.
 	return true
 }
 
-func (logger *Logger) Add(message string, pos Pos) {
-	logger.errors = append(logger.errors, Error{
+func (logger *Logger) Add(message string, span Span) {
+	logger.errors.errors = append(logger.errors.errors, Error{
 		Message: message,
-		Pos:     pos,
+		Span:    span,
 	})
 }
 
 func (logger *Logger) AddError(err error) {
-	logger.errors = append(logger.errors, *err.(*Error))
+	logger.errors.errors = append(logger.errors.errors, *err.(*Error))
+}
+
+// Trace prints a single line of debug trace output, such as a parser's
+// production trace. It exists as its own sink, separate from fmt.Println,
+// so callers that want to capture or redirect trace output only have to
+// change Logger instead of every call site that produces it.
+func (logger *Logger) Trace(msg string) {
+	fmt.Println(msg)
 }
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// Comment is a single '#' comment token, kept verbatim (leading '#'
+// included) so a future formatter can reproduce it exactly rather than
+// just its text. It is attached to the Expr or TableItem it documents
+// through that node's own Comments field instead of living in the tree on
+// its own, so ordinary tree-walking code (Walk, Fdump) doesn't have to
+// know comments exist.
+type Comment struct {
+	Text        string
+	CommentSpan logger.Span
+}
+
+// attachComments appends comments to expr's own Comments field. It has to
+// type-switch since Expr is an interface with no shared way to reach a
+// field; see the Comments field on each concrete Expr type in expr.go.
+func attachComments(expr Expr, comments ...*Comment) {
+	if len(comments) == 0 {
+		return
+	}
+
+	switch current := expr.(type) {
+	case *ExprBad:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprStr:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprInterp:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprBool:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprInt:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprFloat:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprIdent:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprLet:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprFunc:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprCall:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprRef:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprUnary:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprBinary:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprArray:
+		current.Comments = append(current.Comments, comments...)
+	case *ExprTable:
+		current.Comments = append(current.Comments, comments...)
+	}
+}
+
+// nextReal pulls the next token from source, filtering out and, in
+// ParseComments mode, collecting any comment tokens found along the way
+// instead of letting them reach the grammar, which has no rule for them.
+func (parser *Parser) nextReal() ([]*Comment, scanner.Token) {
+	var comments []*Comment
+
+	for {
+		token := parser.source.Next()
+		if token.Kind != scanner.TokenComment {
+			return comments, *token
+		}
+
+		if parser.mode&ParseComments != 0 {
+			comments = append(comments, &Comment{
+				Text:        token.Value,
+				CommentSpan: token.Span,
+			})
+		}
+	}
+}
+
+// leadComments returns and clears the comments collected immediately
+// before the token currently at peek(), for attaching to whatever node is
+// about to start there (a block-table key or block-array dash).
+func (parser *Parser) leadComments() []*Comment {
+	if len(parser.commentsAhead[0]) == 0 {
+		return nil
+	}
+
+	comments := parser.commentsAhead[0]
+	parser.commentsAhead[0] = nil
+
+	return comments
+}
+
+// lineComment returns and removes, from the comments collected immediately
+// before the current peek() token, the one that shares a source line with
+// prev's end, if any, so it can be attached to the expression that just
+// ended at prev instead of becoming a lead comment for whatever is next.
+// Comments are collected in source order, so only the first one collected
+// can possibly share prev's line.
+func (parser *Parser) lineComment(prev *scanner.Token) *Comment {
+	if len(parser.commentsAhead[0]) == 0 {
+		return nil
+	}
+
+	file := parser.logger.File()
+	if file.Position(parser.commentsAhead[0][0].CommentSpan.Start).Line != file.Position(prev.Span.End).Line {
+		return nil
+	}
+
+	comment := parser.commentsAhead[0][0]
+	parser.commentsAhead[0] = parser.commentsAhead[0][1:]
+
+	return comment
+}
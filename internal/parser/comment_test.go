@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// mustParseWithComments scans source with comments kept (not skipped) and
+// parses it in ParseComments mode, so lead/line comments actually reach the
+// tree instead of being discarded before the parser ever sees them.
+func mustParseWithComments(t *testing.T, source string) Expr {
+	t.Helper()
+
+	log := logger.New("<test>", source)
+
+	s := scanner.New(source, log)
+	s.SetMode(scanner.Default &^ scanner.SkipComments)
+	tokens := s.Scan()
+
+	if log.Log() {
+		t.Fatalf("scan %q: unexpected errors", source)
+	}
+
+	expr := New(scanner.NewTokenSource(tokens), log, ParseComments).ParseDocument()
+
+	if log.Log() {
+		t.Fatalf("parse %q: unexpected errors", source)
+	}
+
+	return expr
+}
+
+func TestLeadCommentAttachesToBlockTableKey(t *testing.T) {
+	expr := mustParseWithComments(t, "# about a\na: 1")
+
+	table, ok := expr.(*ExprTable)
+	if !ok {
+		t.Fatalf("expect *ExprTable, got %T", expr)
+	}
+
+	if len(table.Items) != 1 {
+		t.Fatalf("expect 1 item, got %d", len(table.Items))
+	}
+
+	item := table.Items[0]
+	if len(item.Comments) != 1 || item.Comments[0].Text != "# about a" {
+		t.Errorf(`expect a lead comment "# about a" on the table item, got %v`, item.Comments)
+	}
+}
+
+func TestLineCommentAttachesToPrecedingValue(t *testing.T) {
+	expr := mustParseWithComments(t, "a: 1 # inline\nb: 2")
+
+	table := expr.(*ExprTable)
+
+	if len(table.Items) != 2 {
+		t.Fatalf("expect 2 items, got %d", len(table.Items))
+	}
+
+	first := table.Items[0]
+	if len(first.Comments) != 1 || first.Comments[0].Text != "# inline" {
+		t.Errorf(`expect a line comment "# inline" on the first item, got %v`, first.Comments)
+	}
+
+	second := table.Items[1]
+	if len(second.Comments) != 0 {
+		t.Errorf("expect the second item to have no comments, got %v", second.Comments)
+	}
+}
+
+func TestCommentsDiscardedWithoutParseCommentsMode(t *testing.T) {
+	log := logger.New("<test>", "# about a\na: 1")
+
+	s := scanner.New("# about a\na: 1", log)
+	s.SetMode(scanner.Default &^ scanner.SkipComments)
+	tokens := s.Scan()
+
+	expr := New(scanner.NewTokenSource(tokens), log, Default).ParseDocument()
+
+	if log.Log() {
+		t.Fatalf("unexpected errors")
+	}
+
+	table := expr.(*ExprTable)
+
+	if len(table.Items[0].Comments) != 0 {
+		t.Errorf("expect no comments attached outside ParseComments mode, got %v", table.Items[0].Comments)
+	}
+}
@@ -6,18 +6,40 @@ import (
 	"github.com/HereIsKevin/edible/internal/logger"
 )
 
-// TODO: Resolve line and character instead of span.
 // TODO: Create wrapping struct around interface to expose position.
 type Expr interface {
 	Span() logger.Span
 	fmt.Stringer
 }
 
+// Every Expr and TableItem carries its own Comments field: in ParseComments
+// mode, lead comments from the lines immediately before it and a line
+// comment sharing a line with its closing token, in that order. See
+// comment.go.
+
+// Bad is a sentinel node the parser substitutes for an expression it could
+// not parse, so the surrounding tree keeps a valid shape even after an
+// error. BadSpan covers whatever tokens were discarded while recovering.
+
+type ExprBad struct {
+	BadSpan  logger.Span
+	Comments []*Comment
+}
+
+func (bad *ExprBad) Span() logger.Span {
+	return bad.BadSpan
+}
+
+func (bad *ExprBad) String() string {
+	return dumpString(bad)
+}
+
 // String
 
 type ExprStr struct {
 	Value     string
 	ValueSpan logger.Span
+	Comments  []*Comment
 }
 
 func (str *ExprStr) Span() logger.Span {
@@ -25,7 +47,31 @@ func (str *ExprStr) Span() logger.Span {
 }
 
 func (str *ExprStr) String() string {
-	return fmt.Sprintf("Str(\"%s\")", str.Value)
+	return dumpString(str)
+}
+
+// String interpolation
+
+// InterpPart is one piece of an interpolated string: either a literal chunk
+// of text, or, when Value is set, a \(expr) segment to evaluate and splice
+// in as a string.
+type InterpPart struct {
+	Literal string
+	Value   Expr
+}
+
+type ExprInterp struct {
+	Parts     []InterpPart
+	ValueSpan logger.Span
+	Comments  []*Comment
+}
+
+func (interp *ExprInterp) Span() logger.Span {
+	return interp.ValueSpan
+}
+
+func (interp *ExprInterp) String() string {
+	return dumpString(interp)
 }
 
 // Boolean
@@ -33,6 +79,7 @@ func (str *ExprStr) String() string {
 type ExprBool struct {
 	Value     bool
 	ValueSpan logger.Span
+	Comments  []*Comment
 }
 
 func (bool *ExprBool) Span() logger.Span {
@@ -40,7 +87,7 @@ func (bool *ExprBool) Span() logger.Span {
 }
 
 func (bool *ExprBool) String() string {
-	return fmt.Sprintf("Bool(%t)", bool.Value)
+	return dumpString(bool)
 }
 
 // Integer
@@ -48,6 +95,7 @@ func (bool *ExprBool) String() string {
 type ExprInt struct {
 	Value     int64
 	ValueSpan logger.Span
+	Comments  []*Comment
 }
 
 func (int *ExprInt) Span() logger.Span {
@@ -55,7 +103,7 @@ func (int *ExprInt) Span() logger.Span {
 }
 
 func (int *ExprInt) String() string {
-	return fmt.Sprintf("Int(%d)", int.Value)
+	return dumpString(int)
 }
 
 // Float
@@ -63,6 +111,7 @@ func (int *ExprInt) String() string {
 type ExprFloat struct {
 	Value     float64
 	ValueSpan logger.Span
+	Comments  []*Comment
 }
 
 func (float *ExprFloat) Span() logger.Span {
@@ -70,7 +119,95 @@ func (float *ExprFloat) Span() logger.Span {
 }
 
 func (float *ExprFloat) String() string {
-	return fmt.Sprintf("Float(%f)", float.Value)
+	return dumpString(float)
+}
+
+// Identifier
+
+type ExprIdent struct {
+	Name     string
+	NameSpan logger.Span
+	Comments []*Comment
+}
+
+func (ident *ExprIdent) Span() logger.Span {
+	return ident.NameSpan
+}
+
+func (ident *ExprIdent) String() string {
+	return dumpString(ident)
+}
+
+// Let
+
+type LetBinding struct {
+	Name     string
+	NameSpan logger.Span
+	Value    Expr
+}
+
+type ExprLet struct {
+	LetSpan  logger.Span
+	Bindings []*LetBinding
+	Body     Expr
+	Comments []*Comment
+}
+
+func (let *ExprLet) Span() logger.Span {
+	return logger.Span{
+		Start: let.LetSpan.Start,
+		End:   let.Body.Span().End,
+	}
+}
+
+func (let *ExprLet) String() string {
+	return dumpString(let)
+}
+
+// Function
+
+type Param struct {
+	Name     string
+	NameSpan logger.Span
+}
+
+type ExprFunc struct {
+	FuncSpan logger.Span
+	Params   []*Param
+	Body     Expr
+	Comments []*Comment
+}
+
+func (fn *ExprFunc) Span() logger.Span {
+	return logger.Span{
+		Start: fn.FuncSpan.Start,
+		End:   fn.Body.Span().End,
+	}
+}
+
+func (fn *ExprFunc) String() string {
+	return dumpString(fn)
+}
+
+// Call
+
+type ExprCall struct {
+	Callee    Expr
+	Args      []Expr
+	OpenSpan  logger.Span
+	CloseSpan logger.Span
+	Comments  []*Comment
+}
+
+func (call *ExprCall) Span() logger.Span {
+	return logger.Span{
+		Start: call.Callee.Span().Start,
+		End:   call.CloseSpan.End,
+	}
+}
+
+func (call *ExprCall) String() string {
+	return dumpString(call)
 }
 
 // Reference
@@ -97,6 +234,7 @@ type ExprRef struct {
 	Modifier     RefModifier
 	ModifierSpan logger.Span
 	Keys         []Expr
+	Comments     []*Comment
 }
 
 func (ref *ExprRef) Span() logger.Span {
@@ -110,16 +248,7 @@ func (ref *ExprRef) Span() logger.Span {
 }
 
 func (ref *ExprRef) String() string {
-	keys := []string{}
-
-	for _, key := range ref.Keys {
-		keys = append(keys, key.String())
-	}
-
-	return logger.DebugStruct("Ref", []logger.DebugField{
-		{Key: "Modifier", Value: ref.Modifier.String()},
-		{Key: "Keys", Value: logger.DebugSlice(keys)},
-	})
+	return dumpString(ref)
 }
 
 // Unary
@@ -129,6 +258,14 @@ type UnaryOp uint8
 const (
 	UnaryPlus UnaryOp = iota
 	UnaryMinus
+
+	// Schema constraint predicates. Unlike UnaryPlus/UnaryMinus, these do
+	// not compute a value; they describe what a value must satisfy (e.g.
+	// `>=0` inside a schema's `int & >=0`), and are only meaningful to
+	// internal/schema.
+	UnaryGte
+	UnaryLte
+	UnaryMatch
 )
 
 func (op UnaryOp) String() string {
@@ -137,15 +274,22 @@ func (op UnaryOp) String() string {
 		return "Plus"
 	case UnaryMinus:
 		return "Minus"
+	case UnaryGte:
+		return "Gte"
+	case UnaryLte:
+		return "Lte"
+	case UnaryMatch:
+		return "Match"
 	default:
 		return "Unknown"
 	}
 }
 
 type ExprUnary struct {
-	Op     UnaryOp
-	OpSpan logger.Span
-	Right  Expr
+	Op       UnaryOp
+	OpSpan   logger.Span
+	Right    Expr
+	Comments []*Comment
 }
 
 func (unary *ExprUnary) Span() logger.Span {
@@ -156,10 +300,7 @@ func (unary *ExprUnary) Span() logger.Span {
 }
 
 func (unary *ExprUnary) String() string {
-	return logger.DebugStruct("Unary", []logger.DebugField{
-		{Key: "Op", Value: unary.Op.String()},
-		{Key: "Right", Value: unary.Right.String()},
-	})
+	return dumpString(unary)
 }
 
 // Binary
@@ -171,6 +312,20 @@ const (
 	BinaryMinus
 	BinaryStar
 	BinarySlash
+	BinaryEq
+	BinaryNeq
+	BinaryLt
+	BinaryLte
+	BinaryGt
+	BinaryGte
+	BinaryAnd
+	BinaryOr
+
+	// Schema operators: BinaryUnify (&) is CUE-style conjunction (a value
+	// must satisfy both sides), BinaryDisjoin (|) is disjunction (either
+	// side). See internal/schema.
+	BinaryUnify
+	BinaryDisjoin
 )
 
 func (op BinaryOp) String() string {
@@ -183,16 +338,37 @@ func (op BinaryOp) String() string {
 		return "Star"
 	case BinarySlash:
 		return "Slash"
+	case BinaryEq:
+		return "Eq"
+	case BinaryNeq:
+		return "Neq"
+	case BinaryLt:
+		return "Lt"
+	case BinaryLte:
+		return "Lte"
+	case BinaryGt:
+		return "Gt"
+	case BinaryGte:
+		return "Gte"
+	case BinaryAnd:
+		return "And"
+	case BinaryOr:
+		return "Or"
+	case BinaryUnify:
+		return "Unify"
+	case BinaryDisjoin:
+		return "Disjoin"
 	default:
 		return "Unknown"
 	}
 }
 
 type ExprBinary struct {
-	Left   Expr
-	Op     BinaryOp
-	OpSpan logger.Span
-	Right  Expr
+	Left     Expr
+	Op       BinaryOp
+	OpSpan   logger.Span
+	Right    Expr
+	Comments []*Comment
 }
 
 func (binary *ExprBinary) Span() logger.Span {
@@ -203,11 +379,7 @@ func (binary *ExprBinary) Span() logger.Span {
 }
 
 func (binary *ExprBinary) String() string {
-	return logger.DebugStruct("Binary", []logger.DebugField{
-		{Key: "Left", Value: binary.Left.String()},
-		{Key: "Op", Value: binary.Op.String()},
-		{Key: "Right", Value: binary.Right.String()},
-	})
+	return dumpString(binary)
 }
 
 // Array
@@ -216,6 +388,7 @@ type ExprArray struct {
 	OpenSpan  logger.Span
 	Items     []Expr
 	CloseSpan logger.Span
+	Comments  []*Comment
 }
 
 func (array *ExprArray) Span() logger.Span {
@@ -226,43 +399,27 @@ func (array *ExprArray) Span() logger.Span {
 }
 
 func (array *ExprArray) String() string {
-	items := []string{}
-
-	for _, item := range array.Items {
-		items = append(items, item.String())
-	}
-
-	return logger.DebugStruct("Array", []logger.DebugField{
-		{Key: "Items", Value: logger.DebugSlice(items)},
-	})
+	return dumpString(array)
 }
 
 // Table
 
 type TableItem struct {
-	Key    Expr
-	Parent Expr
-	Value  Expr
+	Key      Expr
+	Parent   Expr
+	Value    Expr
+	Comments []*Comment
 }
 
 func (item *TableItem) String() string {
-	parent := "nil"
-
-	if item.Parent != nil {
-		parent = item.Parent.String()
-	}
-
-	return logger.DebugStruct("", []logger.DebugField{
-		{Key: "Key", Value: item.Key.String()},
-		{Key: "Parent", Value: parent},
-		{Key: "Value", Value: item.Value.String()},
-	})
+	return dumpString(item)
 }
 
 type ExprTable struct {
 	OpenSpan  logger.Span
 	Items     []*TableItem
 	CloseSpan logger.Span
+	Comments  []*Comment
 }
 
 func (table *ExprTable) Span() logger.Span {
@@ -273,13 +430,5 @@ func (table *ExprTable) Span() logger.Span {
 }
 
 func (table *ExprTable) String() string {
-	items := []string{}
-
-	for _, item := range table.Items {
-		items = append(items, item.String())
-	}
-
-	return logger.DebugStruct("Table", []logger.DebugField{
-		{Key: "Items", Value: logger.DebugSlice(items)},
-	})
+	return dumpString(table)
 }
@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+)
+
+// Fdump writes a structured, indented dump of node to w, modeled on
+// go/ast.Fdump: every exported field is printed as "Name: value", recursing
+// into nested Expr values, slices of Expr, and *TableItem entries. A
+// logger.Span prints compactly as "start..end". Pointers already visited
+// print as a "p<N>" marker instead of being dumped again, so a tree with
+// shared subtrees doesn't get dumped twice. Unexported fields are skipped.
+func Fdump(w io.Writer, node Expr) error {
+	return fdump(w, node)
+}
+
+// fdump drives the same reflection walk Fdump does, but over any value
+// rather than just an Expr, so supporting types like TableItem (which has
+// no Span of its own) can reuse it from their String methods.
+func fdump(w io.Writer, v interface{}) error {
+	dumper := &dumper{writer: w, ptrs: map[uintptr]int{}}
+	dumper.dump(reflect.ValueOf(v))
+
+	return dumper.err
+}
+
+// dumpString runs fdump against a strings.Builder, the way every Expr's
+// String method renders itself.
+func dumpString(v interface{}) string {
+	builder := &strings.Builder{}
+	fdump(builder, v)
+
+	return builder.String()
+}
+
+type dumper struct {
+	writer io.Writer
+	ptrs   map[uintptr]int
+	depth  int
+	err    error
+}
+
+func (dumper *dumper) printf(format string, args ...interface{}) {
+	if dumper.err != nil {
+		return
+	}
+
+	if _, err := fmt.Fprintf(dumper.writer, format, args...); err != nil {
+		dumper.err = err
+	}
+}
+
+func (dumper *dumper) indent() string {
+	return strings.Repeat("    ", dumper.depth)
+}
+
+func (dumper *dumper) dump(value reflect.Value) {
+	if !value.IsValid() {
+		dumper.printf("nil")
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			dumper.printf("nil")
+			return
+		}
+
+		dumper.dump(value.Elem())
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			dumper.printf("nil")
+			return
+		}
+
+		ptr := value.Pointer()
+		if id, ok := dumper.ptrs[ptr]; ok {
+			dumper.printf("p%d", id)
+			return
+		}
+
+		id := len(dumper.ptrs) + 1
+		dumper.ptrs[ptr] = id
+
+		dumper.printf("p%d ", id)
+		dumper.dump(value.Elem())
+
+	case reflect.Struct:
+		// Spans carry no children worth recursing into, so format them
+		// compactly instead of like a struct.
+		if span, ok := value.Interface().(logger.Span); ok {
+			dumper.printf("%d..%d", span.Start, span.End)
+			return
+		}
+
+		dumper.printf("%s {\n", value.Type())
+		dumper.depth++
+
+		for index := 0; index < value.NumField(); index++ {
+			field := value.Type().Field(index)
+			if field.PkgPath != "" {
+				// Unexported.
+				continue
+			}
+
+			dumper.printf("%s%s: ", dumper.indent(), field.Name)
+			dumper.dump(value.Field(index))
+			dumper.printf("\n")
+		}
+
+		dumper.depth--
+		dumper.printf("%s}", dumper.indent())
+
+	case reflect.Slice, reflect.Array:
+		if value.Len() == 0 {
+			dumper.printf("[]")
+			return
+		}
+
+		dumper.printf("[\n")
+		dumper.depth++
+
+		for index := 0; index < value.Len(); index++ {
+			dumper.printf("%s", dumper.indent())
+			dumper.dump(value.Index(index))
+			dumper.printf(",\n")
+		}
+
+		dumper.depth--
+		dumper.printf("%s]", dumper.indent())
+
+	case reflect.String:
+		dumper.printf("%q", value.String())
+
+	case reflect.Bool:
+		dumper.printf("%t", value.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if stringer, ok := value.Interface().(fmt.Stringer); ok {
+			dumper.printf("%s", stringer.String())
+			return
+		}
+
+		dumper.printf("%d", value.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if stringer, ok := value.Interface().(fmt.Stringer); ok {
+			dumper.printf("%s", stringer.String())
+			return
+		}
+
+		dumper.printf("%d", value.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		dumper.printf("%f", value.Float())
+
+	default:
+		dumper.printf("%v", value.Interface())
+	}
+}
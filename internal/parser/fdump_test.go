@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFdumpWritesFieldsByName(t *testing.T) {
+	expr := mustParseExpr(t, "x: 1")
+
+	var out strings.Builder
+	if err := Fdump(&out, expr); err != nil {
+		t.Fatalf("Fdump: %v", err)
+	}
+
+	dump := out.String()
+
+	for _, want := range []string{"ExprTable", "ExprInt", "Value: 1"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("expect dump to contain %q, got:\n%s", want, dump)
+		}
+	}
+}
+
+func TestFdumpMarksRevisitedPointers(t *testing.T) {
+	shared := &ExprInt{Value: 1}
+	array := &ExprArray{Items: []Expr{shared, shared}}
+
+	var out strings.Builder
+	if err := Fdump(&out, array); err != nil {
+		t.Fatalf("Fdump: %v", err)
+	}
+
+	dump := out.String()
+
+	if strings.Count(dump, "ExprInt {") != 1 {
+		t.Errorf("expect the shared *ExprInt to only be dumped in full once, got:\n%s", dump)
+	}
+
+	// The array itself is p1; the shared *ExprInt is p2, printed once with
+	// its full dump and once more as a bare marker.
+	if strings.Count(dump, "p2") != 2 {
+		t.Errorf("expect the revisited pointer to print as a p<N> marker, got:\n%s", dump)
+	}
+}
+
+func TestExprStringUsesFdump(t *testing.T) {
+	expr := &ExprBool{Value: true}
+
+	if got, want := expr.String(), "ExprBool"; !strings.Contains(got, want) {
+		t.Errorf("expect String() to include %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// parseStr turns a scanned string token into a literal ExprStr, or, if its
+// text contains one or more \(expr) segments, into an ExprInterp whose parts
+// alternate between literal chunks and sub-expressions. Each \(expr) segment
+// is parsed on its own with a fresh scanner and parser, since it is a
+// standalone inline expression rather than part of the enclosing document's
+// token stream.
+func (parser *Parser) parseStr(token *scanner.Token) Expr {
+	if !strings.Contains(token.Value, `\(`) {
+		return &ExprStr{
+			Value:     token.Value,
+			ValueSpan: token.Span,
+		}
+	}
+
+	var parts []InterpPart
+
+	text := token.Value
+
+	for {
+		start := strings.Index(text, `\(`)
+		if start < 0 {
+			if text != "" {
+				parts = append(parts, InterpPart{Literal: text})
+			}
+
+			break
+		}
+
+		if start > 0 {
+			parts = append(parts, InterpPart{Literal: text[:start]})
+		}
+
+		end := matchingParen(text, start+2)
+		if end < 0 {
+			parser.addError("Unterminated '\\(' in string.", token)
+			break
+		}
+
+		expr, err := parseInterpExpr(text[start+2 : end])
+		if err != nil {
+			parser.addError(err.Error(), token)
+		} else {
+			parts = append(parts, InterpPart{Value: expr})
+		}
+
+		text = text[end+1:]
+	}
+
+	return &ExprInterp{
+		Parts:     parts,
+		ValueSpan: token.Span,
+	}
+}
+
+// matchingParen finds the index in text of the ')' that closes the '(' just
+// before openIndex, accounting for parentheses nested inside the segment.
+func matchingParen(text string, openIndex int) int {
+	depth := 1
+
+	for i := openIndex; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// parseInterpExpr parses the text inside a \(...) segment with its own
+// scanner, logger, and parser, the same technique internal/query uses to
+// parse filter expressions out of a larger string.
+func parseInterpExpr(text string) (Expr, error) {
+	log := logger.New("<interp>", text)
+	tokens := scanner.New(text, log).Scan()
+
+	if log.Log() {
+		return nil, fmt.Errorf("invalid expression %q in string interpolation", text)
+	}
+
+	expr := ParseExpr(scanner.NewTokenSource(tokens), log, Default)
+
+	if log.Log() {
+		return nil, fmt.Errorf("invalid expression %q in string interpolation", text)
+	}
+
+	return expr, nil
+}
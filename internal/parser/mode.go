@@ -0,0 +1,22 @@
+package parser
+
+// Mode is a bitmask of optional parser behaviors, mirroring the mode
+// surface of go/parser.ParseFile.
+type Mode uint8
+
+const (
+	// ParseComments tells the parser to attach nearby comment tokens to the
+	// Expr or TableItem they lead or share a line with, instead of just
+	// discarding them. It only has an effect if the Scanner that produced
+	// the token stream was run without scanner.SkipComments, since
+	// otherwise no TokenComment tokens ever reach the parser to attach.
+	ParseComments Mode = 1 << iota
+
+	// Trace tells the parser to print an indented production trace -
+	// current token, position, and nesting depth - on entry and exit of
+	// every parseX method, through logger.Logger.Trace. See trace.go.
+	Trace
+)
+
+// Default is the Mode used by callers that don't need comments attached.
+const Default Mode = 0
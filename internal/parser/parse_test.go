@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+func TestParseExprParsesAStandaloneExpression(t *testing.T) {
+	tokens, log := mustScan(t, "1 + 2")
+
+	expr := ParseExpr(scanner.NewTokenSource(tokens), log, Default)
+
+	if log.Log() {
+		t.Fatalf("unexpected errors")
+	}
+
+	binary, ok := expr.(*ExprBinary)
+	if !ok {
+		t.Fatalf("expect *ExprBinary, got %T", expr)
+	}
+
+	if binary.Op != BinaryPlus {
+		t.Errorf("expect BinaryPlus, got %v", binary.Op)
+	}
+}
+
+func TestParseExprRejectsTrailingTokens(t *testing.T) {
+	tokens, log := mustScan(t, "1 + 2\nx: 3")
+
+	ParseExpr(scanner.NewTokenSource(tokens), log, Default)
+
+	if !log.Log() {
+		t.Fatalf("expect trailing tokens after a standalone expression to be an error")
+	}
+}
+
+func TestParseDocumentAcceptsBlockTableExpr(t *testing.T) {
+	tokens, log := mustScan(t, "x: 1")
+
+	expr := New(scanner.NewTokenSource(tokens), log, Default).ParseDocument()
+
+	if log.Log() {
+		t.Fatalf("unexpected errors")
+	}
+
+	if _, ok := expr.(*ExprTable); !ok {
+		t.Errorf("expect ParseDocument to parse a block table, got %T", expr)
+	}
+}
+
+func TestParseExprRejectsBlockTableSyntax(t *testing.T) {
+	tokens, log := mustScan(t, "x: 1")
+
+	ParseExpr(scanner.NewTokenSource(tokens), log, Default)
+
+	if !log.Log() {
+		t.Fatalf("expect ParseExpr to reject block-table syntax, since it only parses an inline expression")
+	}
+}
+
+func TestNewAcceptsAStreamingScannerSource(t *testing.T) {
+	source := "x: 1 + 2"
+	log := logger.New("<test>", source)
+
+	s := scanner.New(source, log)
+
+	expr := New(scanner.NewScannerSource(s), log, Default).ParseDocument()
+
+	if log.Log() {
+		t.Fatalf("unexpected errors")
+	}
+
+	table, ok := expr.(*ExprTable)
+	if !ok {
+		t.Fatalf("expect *ExprTable, got %T", expr)
+	}
+
+	if len(table.Items) != 1 {
+		t.Fatalf("expect 1 item, got %d", len(table.Items))
+	}
+}
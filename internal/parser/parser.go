@@ -8,46 +8,149 @@ import (
 )
 
 type Parser struct {
-	tokens  scanner.Tokens
-	logger  *logger.Logger
-	current int
+	source scanner.TokenSource
+	logger *logger.Logger
+	config Config
+	mode   Mode
+
+	// lookahead holds the current token and the one after it, the only
+	// amount of lookahead the grammar needs (see parseBlock's peekNext use).
+	// prev is the last token returned by advance.
+	lookahead [2]scanner.Token
+	prev      scanner.Token
+
+	// commentsAhead[i] holds the comment tokens collected immediately
+	// before lookahead[i] was pulled from source, pending attachment to
+	// whatever node starts or ends near that position. Only populated in
+	// ParseComments mode.
+	commentsAhead [2][]*Comment
+
+	// errorCount is the number of diagnostics reported so far. panicking is
+	// set by addError and cleared by sync; it suppresses the cascade of
+	// follow-on errors that a single bad token would otherwise produce
+	// while the parser is looking for a safe place to resume.
+	errorCount int
+	panicking  bool
+
+	// syncPos and syncCount detect a sync call that keeps landing on the
+	// same token with no caller-side progress in between, so sync can force
+	// an advance rather than risk being spun on forever.
+	syncPos   logger.Pos
+	syncCount int
+
+	// traceDepth is the current parseX nesting depth, maintained by
+	// trace/untrace. Only meaningful in Trace mode.
+	traceDepth int
 }
 
-func New(tokens scanner.Tokens, logger *logger.Logger) *Parser {
-	return &Parser{
-		tokens:  tokens,
-		logger:  logger,
-		current: 0,
+// New constructs a Parser pulling from source, which lets callers trade
+// away a fully materialized scanner.Tokens slice (via scanner.NewTokenSource)
+// for whatever TokenSource fits them, such as tokens scanned incrementally.
+func New(source scanner.TokenSource, logger *logger.Logger, mode Mode) *Parser {
+	parser := &Parser{
+		source: source,
+		logger: logger,
+		config: DefaultConfig,
+		mode:   mode,
 	}
+
+	parser.commentsAhead[0], parser.lookahead[0] = parser.nextReal()
+	parser.commentsAhead[1], parser.lookahead[1] = parser.nextReal()
+
+	return parser
 }
 
-func (parser *Parser) Parse() Expr {
-	expr := parser.parseBlock()
+// ParseDocument parses the whole token stream into a single Expr, following
+// the top-level block grammar a whole file uses. See ParseExpr for parsing
+// just a standalone inline expression, the way go/parser.ParseFile and
+// go/parser.ParseExpr split a file from a fragment.
+func (parser *Parser) ParseDocument() Expr {
+	return parser.parseRecover(parser.parseBlock)
+}
+
+// ParseExpr parses source as a single inline expression rather than a whole
+// document, for callers that embed edible expressions without a surrounding
+// file, such as a templating layer or a REPL.
+func ParseExpr(source scanner.TokenSource, logger *logger.Logger, mode Mode) Expr {
+	parser := New(source, logger, mode)
+
+	return parser.parseRecover(parser.parseInline)
+}
+
+// parseRecover runs parse, then checks that it consumed the whole stream.
+// Once too many errors pile up, addError panics with bailout to unwind
+// straight here instead of leaving every helper in between to notice and
+// give up on its own; recover stands in with an ExprBad spanning whatever is
+// left so callers still get a valid tree back. Both ParseDocument and
+// ParseExpr share it so the bailout safety net only has to be written once.
+func (parser *Parser) parseRecover(parse func() Expr) (expr Expr) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+
+			expr = &ExprBad{BadSpan: logger.Span{Start: parser.peek().Span.Start, End: parser.peek().Span.End}}
+		}
+	}()
+
+	expr = parse()
 
 	if !parser.isEOF() {
 		parser.addError("Unexpected token.", parser.peek())
 	}
 
+	if expr == nil {
+		// parse can give up and return nil without ever panicking with
+		// bailout (an error under the limit, recovered from by simply
+		// propagating nil up through the recursive-descent chain), so nil
+		// has to be caught here too, not just in the deferred recover, or
+		// callers relying on this function's "always a valid tree"
+		// contract would see a nil Expr instead.
+		expr = &ExprBad{BadSpan: logger.Span{Start: parser.peek().Span.Start, End: parser.peek().Span.End}}
+	}
+
 	return expr
 }
 
 func (parser *Parser) parseBlock() Expr {
+	defer untrace(trace(parser, "parseBlock"))
+
 	switch parser.peek().Kind {
+	// A leading newline never reaches here while the scanner skips
+	// comments (scanner.Default): it only emits one once something has
+	// already been emitted on the same line or before it. A scanner that
+	// keeps comments, though, counts a leading comment-only line as
+	// "something emitted" for that purpose, whatever this parser's Mode,
+	// so the newline ending it still arrives here as if it belonged to
+	// real content. Skip it rather than erroring to keep a lead comment
+	// on an otherwise blank opening line from breaking the parse.
+	case scanner.TokenNewline:
+		// Carry forward any comment collected immediately before this
+		// newline (such a lead comment on an otherwise blank opening line)
+		// onto whatever precedes the next real token, since the newline
+		// itself is about to be discarded and has no node to attach to.
+		carried := parser.commentsAhead[0]
+		parser.advance()
+		parser.commentsAhead[0] = append(carried, parser.commentsAhead[0]...)
+
+		return parser.parseBlock()
+
 	// Block expression
 	case scanner.TokenOpenBlock:
 		// Consume block open.
 		parser.advance()
 
-		// Consume expression and possibly exit fatally.
+		// Consume expression, synchronizing past anything that did not
+		// parse instead of aborting the whole block.
 		expr := parser.parseBlock()
 		if expr == nil {
-			return nil
+			expr = parser.sync()
 		}
 
 		// Consume block closing.
-		token := parser.consume(scanner.TokenCloseBlock, "Expect dedent after block")
-		if token == nil {
-			return nil
+		if parser.consume(scanner.TokenCloseBlock, "Expect dedent after block") == nil {
+			parser.sync()
 		}
 
 		return expr
@@ -76,24 +179,42 @@ func (parser *Parser) parseBlock() Expr {
 }
 
 func (parser *Parser) parseBlockArray() Expr {
+	defer untrace(trace(parser, "parseBlockArray"))
+
 	items := []Expr{}
 
 	// Use position of first dash as start.
-	openPos := parser.peek().Pos
+	openPos := parser.peek().Span
 
 	for parser.peek().Kind == scanner.TokenDash {
+		// Grab any comments on the lines immediately before this dash, to
+		// attach as the item's lead comments once it is parsed.
+		lead := parser.leadComments()
+
 		// Consume dash.
 		parser.advance()
 
-		// Consume expression.
+		// Consume expression, synchronizing to the next item instead of
+		// abandoning the rest of the array on a single bad element.
 		expr := parser.parseBlock()
 		if expr == nil {
-			return nil
+			expr = parser.sync()
+		}
+
+		attachComments(expr, lead...)
+
+		if comment := parser.lineComment(parser.previous()); comment != nil {
+			attachComments(expr, comment)
 		}
 
 		// Add expression as item.
 		items = append(items, expr)
 
+		// Stop entirely once too many errors have piled up.
+		if parser.exceeded() {
+			break
+		}
+
 		// Finished if there is no newline.
 		if parser.peek().Kind != scanner.TokenNewline {
 			break
@@ -104,36 +225,46 @@ func (parser *Parser) parseBlockArray() Expr {
 	}
 
 	// Use position of last token as end.
-	closePos := parser.previous().Pos
+	closePos := parser.previous().Span
 
 	return &ExprArray{
-		Items: items,
-		Position: logger.Pos{
-			Start: openPos.Start,
-			End:   closePos.End,
-			Line:  openPos.Line,
-		},
+		OpenSpan:  openPos,
+		Items:     items,
+		CloseSpan: closePos,
 	}
 }
 
 func (parser *Parser) parseBlockTable() Expr {
+	defer untrace(trace(parser, "parseBlockTable"))
+
 	items := []*TableItem{}
 
 	// Use position of first key as start.
-	openPos := parser.peek().Pos
+	openPos := parser.peek().Span
 
 	for parser.peek().Kind == scanner.TokenStr ||
 		parser.peek().Kind == scanner.TokenIdent {
 
+		// Grab any comments on the lines immediately before this key, to
+		// attach as the item's lead comments once it is parsed.
+		lead := parser.leadComments()
+
 		// Consume table item.
 		item := parser.parseTableItem(parser.parseBlock)
-		if item == nil {
-			return nil
+		item.Comments = append(item.Comments, lead...)
+
+		if comment := parser.lineComment(parser.previous()); comment != nil {
+			item.Comments = append(item.Comments, comment)
 		}
 
 		// Add item to table.
 		items = append(items, item)
 
+		// Stop entirely once too many errors have piled up.
+		if parser.exceeded() {
+			break
+		}
+
 		// Finished if there is no newline.
 		if parser.peek().Kind != scanner.TokenNewline {
 			break
@@ -144,23 +275,212 @@ func (parser *Parser) parseBlockTable() Expr {
 	}
 
 	// Use position of last token as end.
-	closePos := parser.previous().Pos
+	closePos := parser.previous().Span
 
 	return &ExprTable{
-		Items: items,
-		Position: logger.Pos{
-			Start: openPos.Start,
-			End:   closePos.End,
-			Line:  openPos.Line,
-		},
+		OpenSpan:  openPos,
+		Items:     items,
+		CloseSpan: closePos,
 	}
 }
 
 func (parser *Parser) parseInline() Expr {
-	return parser.parseTerm()
+	defer untrace(trace(parser, "parseInline"))
+
+	return parser.parseDisjoin()
+}
+
+// parseDisjoin and parseUnify parse the schema operators | and &. They sit
+// above parseOr/parseAnd in precedence (looser-binding), matching CUE, where
+// a schema like `int & >=0 | string` composes whole constraints rather than
+// booleans.
+func (parser *Parser) parseDisjoin() Expr {
+	defer untrace(trace(parser, "parseDisjoin"))
+
+	expr := parser.parseUnify()
+	if expr == nil {
+		return nil
+	}
+
+	for parser.peek().Kind == scanner.TokenPipe {
+		pos := parser.advance().Span
+		right := parser.parseUnify()
+		if right == nil {
+			return nil
+		}
+
+		expr = &ExprBinary{
+			Left:   expr,
+			Op:     BinaryDisjoin,
+			OpSpan: pos,
+			Right:  right,
+		}
+	}
+
+	return expr
+}
+
+func (parser *Parser) parseUnify() Expr {
+	defer untrace(trace(parser, "parseUnify"))
+
+	expr := parser.parseOr()
+	if expr == nil {
+		return nil
+	}
+
+	for parser.peek().Kind == scanner.TokenAmp {
+		pos := parser.advance().Span
+		right := parser.parseOr()
+		if right == nil {
+			return nil
+		}
+
+		expr = &ExprBinary{
+			Left:   expr,
+			Op:     BinaryUnify,
+			OpSpan: pos,
+			Right:  right,
+		}
+	}
+
+	return expr
+}
+
+func (parser *Parser) parseOr() Expr {
+	defer untrace(trace(parser, "parseOr"))
+
+	expr := parser.parseAnd()
+	if expr == nil {
+		return nil
+	}
+
+	for parser.peek().Kind == scanner.TokenPipePipe {
+		pos := parser.advance().Span
+		right := parser.parseAnd()
+		if right == nil {
+			return nil
+		}
+
+		expr = &ExprBinary{
+			Left:   expr,
+			Op:     BinaryOr,
+			OpSpan: pos,
+			Right:  right,
+		}
+	}
+
+	return expr
+}
+
+func (parser *Parser) parseAnd() Expr {
+	defer untrace(trace(parser, "parseAnd"))
+
+	expr := parser.parseEquality()
+	if expr == nil {
+		return nil
+	}
+
+	for parser.peek().Kind == scanner.TokenAmpAmp {
+		pos := parser.advance().Span
+		right := parser.parseEquality()
+		if right == nil {
+			return nil
+		}
+
+		expr = &ExprBinary{
+			Left:   expr,
+			Op:     BinaryAnd,
+			OpSpan: pos,
+			Right:  right,
+		}
+	}
+
+	return expr
+}
+
+func (parser *Parser) parseEquality() Expr {
+	defer untrace(trace(parser, "parseEquality"))
+
+	expr := parser.parseComparison()
+	if expr == nil {
+		return nil
+	}
+
+loop:
+	for {
+		var op BinaryOp
+
+		switch parser.peek().Kind {
+		case scanner.TokenEqualEqual:
+			op = BinaryEq
+		case scanner.TokenBangEqual:
+			op = BinaryNeq
+		default:
+			break loop
+		}
+
+		pos := parser.advance().Span
+		right := parser.parseComparison()
+		if right == nil {
+			return nil
+		}
+
+		expr = &ExprBinary{
+			Left:   expr,
+			Op:     op,
+			OpSpan: pos,
+			Right:  right,
+		}
+	}
+
+	return expr
+}
+
+func (parser *Parser) parseComparison() Expr {
+	defer untrace(trace(parser, "parseComparison"))
+
+	expr := parser.parseTerm()
+	if expr == nil {
+		return nil
+	}
+
+loop:
+	for {
+		var op BinaryOp
+
+		switch parser.peek().Kind {
+		case scanner.TokenLess:
+			op = BinaryLt
+		case scanner.TokenLessEqual:
+			op = BinaryLte
+		case scanner.TokenGreater:
+			op = BinaryGt
+		case scanner.TokenGreaterEqual:
+			op = BinaryGte
+		default:
+			break loop
+		}
+
+		pos := parser.advance().Span
+		right := parser.parseTerm()
+		if right == nil {
+			return nil
+		}
+
+		expr = &ExprBinary{
+			Left:   expr,
+			Op:     op,
+			OpSpan: pos,
+			Right:  right,
+		}
+	}
+
+	return expr
 }
 
 func (parser *Parser) parseTerm() Expr {
+	defer untrace(trace(parser, "parseTerm"))
+
 	expr := parser.parseFactor()
 	if expr == nil {
 		return nil
@@ -179,17 +499,17 @@ loop:
 			break loop
 		}
 
-		pos := parser.advance().Pos
+		pos := parser.advance().Span
 		right := parser.parseFactor()
 		if right == nil {
 			return nil
 		}
 
 		expr = &ExprBinary{
-			Left:     expr,
-			Op:       op,
-			Right:    right,
-			Position: pos,
+			Left:   expr,
+			Op:     op,
+			OpSpan: pos,
+			Right:  right,
 		}
 	}
 
@@ -197,6 +517,8 @@ loop:
 }
 
 func (parser *Parser) parseFactor() Expr {
+	defer untrace(trace(parser, "parseFactor"))
+
 	expr := parser.parseUnary()
 	if expr == nil {
 		return nil
@@ -215,17 +537,17 @@ loop:
 			break loop
 		}
 
-		pos := parser.advance().Pos
+		pos := parser.advance().Span
 		right := parser.parseUnary()
 		if right == nil {
 			return nil
 		}
 
 		expr = &ExprBinary{
-			Left:     expr,
-			Op:       op,
-			Right:    right,
-			Position: pos,
+			Left:   expr,
+			Op:     op,
+			OpSpan: pos,
+			Right:  right,
 		}
 	}
 
@@ -233,6 +555,8 @@ loop:
 }
 
 func (parser *Parser) parseUnary() Expr {
+	defer untrace(trace(parser, "parseUnary"))
+
 	var op UnaryOp
 
 	switch parser.peek().Kind {
@@ -240,33 +564,114 @@ func (parser *Parser) parseUnary() Expr {
 		op = UnaryPlus
 	case scanner.TokenMinus:
 		op = UnaryMinus
+
+	// Schema constraint predicates: `>=0`, `<=100`, `~"regex"` describe
+	// what a value must satisfy rather than computing one; see
+	// internal/schema.
+	case scanner.TokenGreaterEqual:
+		op = UnaryGte
+	case scanner.TokenLessEqual:
+		op = UnaryLte
+	case scanner.TokenTilde:
+		op = UnaryMatch
+
 	default:
-		return parser.parseLiteral()
+		return parser.parseCall()
 	}
 
-	pos := parser.advance().Pos
+	pos := parser.advance().Span
 	expr := parser.parseUnary()
 	if expr == nil {
 		return nil
 	}
 
 	return &ExprUnary{
-		Op:       op,
-		Right:    expr,
-		Position: pos,
+		Op:     op,
+		OpSpan: pos,
+		Right:  expr,
+	}
+}
+
+// parseCall parses a literal, then any number of trailing call argument
+// lists applied to it, left to right, so `f(1)(2)` calls the result of
+// `f(1)` with `2`.
+func (parser *Parser) parseCall() Expr {
+	defer untrace(trace(parser, "parseCall"))
+
+	expr := parser.parseLiteral()
+	if expr == nil {
+		return nil
+	}
+
+	for parser.peek().Kind == scanner.TokenOpenParen {
+		expr = parser.parseCallArgs(expr)
+	}
+
+	return expr
+}
+
+// parseCallArgs parses a parenthesized, comma-separated argument list and
+// wraps callee in the resulting ExprCall. The open parenthesis is still
+// unconsumed when this is called.
+func (parser *Parser) parseCallArgs(callee Expr) Expr {
+	defer untrace(trace(parser, "parseCallArgs"))
+
+	args := []Expr{}
+
+	// Consume opening parenthesis.
+	openPos := parser.advance().Span
+
+	for !parser.isEOF() && parser.peek().Kind != scanner.TokenCloseParen {
+		// Consume argument, synchronizing to the next one instead of
+		// abandoning the rest of the call on a single bad argument.
+		arg := parser.parseInline()
+		if arg == nil {
+			arg = parser.sync()
+		}
+
+		args = append(args, arg)
+
+		if parser.exceeded() {
+			break
+		}
+
+		// Check for comma if not at closing parenthesis, otherwise just
+		// repeat.
+		if parser.peek().Kind != scanner.TokenCloseParen {
+			if parser.consume(scanner.TokenComma, "Expect ',' between arguments.") == nil {
+				parser.sync()
+			}
+		}
+	}
+
+	// Consume closing parenthesis.
+	token := parser.consume(scanner.TokenCloseParen, "Expect ')' after arguments.")
+
+	// Take position from closing parenthesis, falling back to the last
+	// token consumed if the closing parenthesis itself never showed up.
+	closePos := parser.previous().Span
+
+	if token != nil {
+		closePos = token.Span
+	}
+
+	return &ExprCall{
+		Callee:    callee,
+		Args:      args,
+		OpenSpan:  openPos,
+		CloseSpan: closePos,
 	}
 }
 
 func (parser *Parser) parseLiteral() Expr {
+	defer untrace(trace(parser, "parseLiteral"))
+
 	switch parser.peek().Kind {
 	// String
 	case scanner.TokenStr:
 		token := parser.advance()
 
-		return &ExprStr{
-			Value:    token.Value,
-			Position: token.Pos,
-		}
+		return parser.parseStr(token)
 
 	// Identifier, should only be keywords
 	case scanner.TokenIdent:
@@ -275,20 +680,29 @@ func (parser *Parser) parseLiteral() Expr {
 		switch token.Value {
 		case "true":
 			return &ExprBool{
-				Value:    true,
-				Position: token.Pos,
+				Value:     true,
+				ValueSpan: token.Span,
 			}
 
 		case "false":
 			return &ExprBool{
-				Value:    false,
-				Position: token.Pos,
+				Value:     false,
+				ValueSpan: token.Span,
 			}
 
+		case "let":
+			return parser.parseLet(token.Span)
+
+		case "func":
+			return parser.parseFunc(token.Span)
+
 		default:
-			// Fatal, cannot recover from random identifiers that are not keywords.
-			parser.addError("Unexpected identifier.", parser.previous())
-			return nil
+			// Any other identifier is a reference to a let binding or, failing
+			// that, a key in the current table.
+			return &ExprIdent{
+				Name:     token.Value,
+				NameSpan: token.Span,
+			}
 		}
 
 	// Integer
@@ -301,8 +715,8 @@ func (parser *Parser) parseLiteral() Expr {
 		}
 
 		return &ExprInt{
-			Value:    value,
-			Position: token.Pos,
+			Value:     value,
+			ValueSpan: token.Span,
 		}
 
 	// Float
@@ -315,8 +729,8 @@ func (parser *Parser) parseLiteral() Expr {
 		}
 
 		return &ExprFloat{
-			Value:    value,
-			Position: token.Pos,
+			Value:     value,
+			ValueSpan: token.Span,
 		}
 
 	// Grouping
@@ -351,18 +765,137 @@ func (parser *Parser) parseLiteral() Expr {
 		return parser.parseInlineTable()
 
 	default:
-		// Fatal, cannot recover from random tokens.
-		parser.addError("Expect literal.", parser.previous())
+		// Fatal, cannot recover from random tokens. Report at the offending
+		// token itself (peek), not previous: previous is whatever was
+		// consumed last, which for a bad first token in the whole document
+		// is the parser's never-set zero-value prev field, pointing nowhere.
+		parser.addError("Expect literal.", parser.peek())
 		return nil
 	}
 }
 
+// parseLet parses the bindings and body of a `let name = expr, ... in body`
+// expression. letSpan is the span of the already-consumed 'let' keyword.
+func (parser *Parser) parseLet(letSpan logger.Span) Expr {
+	defer untrace(trace(parser, "parseLet"))
+
+	bindings := []*LetBinding{}
+
+	for {
+		// Consume binding name. Name and NameSpan are copied out right away,
+		// since name aliases the parser's single prev field and would
+		// otherwise read back whatever token is consumed last, not the
+		// identifier itself, once '=' and the value have been parsed.
+		name := parser.consume(scanner.TokenIdent, "Expect identifier for let binding.")
+		if name == nil {
+			return parser.sync()
+		}
+
+		bindingName, bindingNameSpan := name.Value, name.Span
+
+		// Consume '='.
+		if parser.consume(scanner.TokenEqual, "Expect '=' after binding name.") == nil {
+			return parser.sync()
+		}
+
+		// Consume binding value.
+		value := parser.parseInline()
+		if value == nil {
+			value = parser.sync()
+		}
+
+		bindings = append(bindings, &LetBinding{
+			Name:     bindingName,
+			NameSpan: bindingNameSpan,
+			Value:    value,
+		})
+
+		// More bindings follow a comma, otherwise expect 'in'.
+		if parser.peek().Kind != scanner.TokenComma {
+			break
+		}
+
+		parser.advance()
+	}
+
+	// 'in' is only ever a keyword here, not a dedicated token kind.
+	if parser.peek().Kind != scanner.TokenIdent || parser.peek().Value != "in" {
+		parser.addError("Expect 'in' after let bindings.", parser.peek())
+		return parser.sync()
+	}
+
+	parser.advance()
+
+	// Consume body expression.
+	body := parser.parseInline()
+	if body == nil {
+		body = parser.sync()
+	}
+
+	return &ExprLet{
+		LetSpan:  letSpan,
+		Bindings: bindings,
+		Body:     body,
+	}
+}
+
+// parseFunc parses the parameter list and body of a `func(name, ...) body`
+// expression. funcSpan is the span of the already-consumed 'func' keyword.
+func (parser *Parser) parseFunc(funcSpan logger.Span) Expr {
+	defer untrace(trace(parser, "parseFunc"))
+
+	if parser.consume(scanner.TokenOpenParen, "Expect '(' after 'func'.") == nil {
+		return parser.sync()
+	}
+
+	params := []*Param{}
+
+	for !parser.isEOF() && parser.peek().Kind != scanner.TokenCloseParen {
+		// Consume parameter name.
+		name := parser.consume(scanner.TokenIdent, "Expect parameter name.")
+		if name == nil {
+			return parser.sync()
+		}
+
+		params = append(params, &Param{
+			Name:     name.Value,
+			NameSpan: name.Span,
+		})
+
+		// Check for comma if not at closing parenthesis, otherwise just
+		// repeat.
+		if parser.peek().Kind != scanner.TokenCloseParen {
+			if parser.consume(scanner.TokenComma, "Expect ',' between parameters.") == nil {
+				return parser.sync()
+			}
+		}
+	}
+
+	if parser.consume(scanner.TokenCloseParen, "Expect ')' after parameters.") == nil {
+		return parser.sync()
+	}
+
+	// Consume body expression.
+	body := parser.parseInline()
+	if body == nil {
+		body = parser.sync()
+	}
+
+	return &ExprFunc{
+		FuncSpan: funcSpan,
+		Params:   params,
+		Body:     body,
+	}
+}
+
 func (parser *Parser) parseRef() Expr {
+	defer untrace(trace(parser, "parseRef"))
+
 	keys := []Expr{}
 
 	// Consume modifier.
 	modifierToken := parser.advance()
-	modifierPos := modifierToken.Pos
+	modifierSpan := modifierToken.Span
 	modifier := RefRelative
 
 	// Change to absolute refernce if there is an absolute modifier.
@@ -374,8 +907,8 @@ func (parser *Parser) parseRef() Expr {
 	if parser.peek().Kind == scanner.TokenIdent {
 		token := parser.advance()
 		keys = append(keys, &ExprStr{
-			Value:    token.Value,
-			Position: token.Pos,
+			Value:     token.Value,
+			ValueSpan: token.Span,
 		})
 	}
 
@@ -401,7 +934,7 @@ loop:
 
 			keys = append(keys, &ExprStr{
 				Value:    token.Value,
-				Position: token.Pos,
+				ValueSpan: token.Span,
 			})
 
 		// Expression key
@@ -430,107 +963,111 @@ loop:
 	}
 
 	return &ExprRef{
-		Modifier: modifier,
-		Keys:     keys,
-		Position: logger.Pos{
-			Start: modifierPos.Start,
-			End:   parser.previous().Pos.End,
-			Line:  modifierPos.Line,
-		},
+		Modifier:     modifier,
+		ModifierSpan: modifierSpan,
+		Keys:         keys,
 	}
 }
 
 func (parser *Parser) parseInlineArray() Expr {
+	defer untrace(trace(parser, "parseInlineArray"))
+
 	items := []Expr{}
 
 	// Consume opening bracket and take position.
-	openPos := parser.advance().Pos
+	openPos := parser.advance().Span
 
-	for parser.peek().Kind != scanner.TokenCloseBrack {
-		// Consume expression.
+	for !parser.isEOF() && parser.peek().Kind != scanner.TokenCloseBrack {
+		// Consume expression, synchronizing to the next item instead of
+		// abandoning the rest of the array on a single bad element.
 		expr := parser.parseInline()
 		if expr == nil {
-			return nil
+			expr = parser.sync()
 		}
 
 		// Add expression as item.
 		items = append(items, expr)
 
+		if parser.exceeded() {
+			break
+		}
+
 		// Check for comma if not at closing bracket, otherwise just repeat.
 		if parser.peek().Kind != scanner.TokenCloseBrack {
 			// Consume comma.
-			token := parser.consume(scanner.TokenComma, "Expect ',' between items.")
-			if token == nil {
-				return nil
+			if parser.consume(scanner.TokenComma, "Expect ',' between items.") == nil {
+				parser.sync()
 			}
 		}
 	}
 
 	// Consume closing bracket.
 	token := parser.consume(scanner.TokenCloseBrack, "Expect ']' after array.")
-	if token == nil {
-		return nil
-	}
 
-	// Take position from closing bracket.
-	closePos := token.Pos
+	// Take position from closing bracket, falling back to the last token
+	// consumed if the closing bracket itself never showed up.
+	closePos := parser.previous().Span
+
+	if token != nil {
+		closePos = token.Span
+	}
 
 	return &ExprArray{
-		Items: items,
-		Position: logger.Pos{
-			Start: openPos.Start,
-			End:   closePos.End,
-			Line:  openPos.Line,
-		},
+		OpenSpan:  openPos,
+		Items:     items,
+		CloseSpan: closePos,
 	}
 }
 
 func (parser *Parser) parseInlineTable() Expr {
+	defer untrace(trace(parser, "parseInlineTable"))
+
 	items := []*TableItem{}
 
 	// Consume opening brace.
-	openPos := parser.advance().Pos
+	openPos := parser.advance().Span
 
-	for parser.peek().Kind != scanner.TokenCloseBrace {
+	for !parser.isEOF() && parser.peek().Kind != scanner.TokenCloseBrace {
 		// Consume table item.
 		item := parser.parseTableItem(parser.parseInline)
-		if item == nil {
-			return nil
-		}
 
 		// Add item to table.
 		items = append(items, item)
 
+		if parser.exceeded() {
+			break
+		}
+
 		// Check for comma if not at closing brace, otherwise just repeat.
 		if parser.peek().Kind != scanner.TokenCloseBrace {
 			// Consume comma.
-			token := parser.consume(scanner.TokenComma, "Expect ',' between items.")
-			if token == nil {
-				return nil
+			if parser.consume(scanner.TokenComma, "Expect ',' between items.") == nil {
+				parser.sync()
 			}
 		}
 	}
 
 	// Consume closing brace.
 	token := parser.consume(scanner.TokenCloseBrace, "Expect '}' after table.")
-	if token == nil {
-		return nil
-	}
 
-	// Take position from closing brace.
-	closePos := token.Pos
+	// Take position from closing brace, falling back to the last token
+	// consumed if the closing brace itself never showed up.
+	closePos := parser.previous().Span
+
+	if token != nil {
+		closePos = token.Span
+	}
 
 	return &ExprTable{
-		Items: items,
-		Position: logger.Pos{
-			Start: openPos.Start,
-			End:   closePos.End,
-			Line:  openPos.Line,
-		},
+		OpenSpan:  openPos,
+		Items:     items,
+		CloseSpan: closePos,
 	}
 }
 
 func (parser *Parser) parseTableItem(valueParser func() Expr) *TableItem {
+	defer untrace(trace(parser, "parseTableItem"))
+
 	var key Expr
 
 	if parser.peek().Kind == scanner.TokenStr ||
@@ -541,13 +1078,14 @@ func (parser *Parser) parseTableItem(valueParser func() Expr) *TableItem {
 
 		// Create string expression for key.
 		key = &ExprStr{
-			Value:    token.Value,
-			Position: token.Pos,
+			Value:     token.Value,
+			ValueSpan: token.Span,
 		}
 	} else {
-		// Fatal, cannot recover from missing key.
+		// Synchronize instead of abandoning the whole table over one bad
+		// key.
 		parser.addError("Expect string or identifier for key.", parser.peek())
-		return nil
+		key = parser.sync()
 	}
 
 	var parent Expr
@@ -560,20 +1098,19 @@ func (parser *Parser) parseTableItem(valueParser func() Expr) *TableItem {
 		// Consume parent expression.
 		parent = parser.parseInline()
 		if parent == nil {
-			return nil
+			parent = parser.sync()
 		}
 	}
 
 	// Consume colon separator.
-	token := parser.consume(scanner.TokenColon, "Expect ':' beween key and value.")
-	if token == nil {
-		return nil
+	if parser.consume(scanner.TokenColon, "Expect ':' beween key and value.") == nil {
+		parser.sync()
 	}
 
 	// Consume value expression.
 	value := valueParser()
 	if value == nil {
-		return nil
+		value = parser.sync()
 	}
 
 	return &TableItem{
@@ -598,28 +1135,44 @@ func (parser *Parser) consume(expected scanner.TokenKind, message string) *scann
 
 func (parser *Parser) advance() *scanner.Token {
 	if !parser.isEOF() {
-		parser.current++
+		parser.prev = parser.lookahead[0]
+		parser.lookahead[0] = parser.lookahead[1]
+		parser.commentsAhead[0] = parser.commentsAhead[1]
+		parser.commentsAhead[1], parser.lookahead[1] = parser.nextReal()
+	} else {
+		parser.prev = parser.lookahead[0]
 	}
 
-	return parser.previous()
+	return &parser.prev
 }
 
 func (parser *Parser) previous() *scanner.Token {
-	return &parser.tokens[parser.current-1]
+	return &parser.prev
 }
 
 func (parser *Parser) peek() *scanner.Token {
-	return &parser.tokens[parser.current]
+	return &parser.lookahead[0]
 }
 
 func (parser *Parser) peekNext() *scanner.Token {
-	if len(parser.tokens) > parser.current+1 {
-		return &parser.tokens[parser.current+1]
-	}
-
-	return &parser.tokens[len(parser.tokens)-1]
+	return &parser.lookahead[1]
 }
 
+// addError reports a diagnostic, unless the parser is still in panic mode
+// from a prior error it hasn't synchronized past yet, which would only add
+// noise about the same malformed region. Once the diagnostic count passes
+// the configured limit, it panics with bailout rather than letting the
+// parser keep limping through an increasingly unreadable document.
 func (parser *Parser) addError(message string, token *scanner.Token) {
-	parser.logger.Add(message, token.Pos)
+	if parser.panicking {
+		return
+	}
+
+	parser.panicking = true
+	parser.errorCount++
+	parser.logger.Add(message, token.Span)
+
+	if parser.exceeded() {
+		panic(bailout{})
+	}
 }
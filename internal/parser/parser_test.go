@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// mustScan scans source, failing t immediately if scanning reports any
+// error, since these tests care about parser recovery, not scanner errors.
+func mustScan(t *testing.T, source string) (scanner.Tokens, *logger.Logger) {
+	t.Helper()
+
+	log := logger.New("<test>", source)
+	tokens := scanner.New(source, log).Scan()
+
+	if log.Log() {
+		t.Fatalf("scan %q: unexpected errors", source)
+	}
+
+	return tokens, log
+}
+
+func TestParseDocumentRecoversFromMalformedInput(t *testing.T) {
+	tokens, log := mustScan(t, "x: 1\ny: )\nz: 2")
+
+	expr := New(scanner.NewTokenSource(tokens), log, Default).ParseDocument()
+
+	if expr == nil {
+		t.Fatalf("expect ParseDocument to return a tree even after an error")
+	}
+
+	if !log.Log() {
+		t.Fatalf("expect malformed input to report an error")
+	}
+}
+
+func TestParseDocumentNeverReturnsNil(t *testing.T) {
+	for _, source := range []string{")", "((((", "}{}{", ":::"} {
+		tokens, log := mustScan(t, source)
+
+		expr := New(scanner.NewTokenSource(tokens), log, Default).ParseDocument()
+
+		if expr == nil {
+			t.Errorf("ParseDocument(%q): expect a non-nil tree, got nil", source)
+		}
+
+		log.Log()
+	}
+}
+
+func TestParseDocumentBailsOutAfterMaxErrors(t *testing.T) {
+	// Inside an array, every ')' is both a missing literal and a missing
+	// ',', so repeating it drives errorCount past a small configured limit.
+	tokens, log := mustScan(t, "[), ), ), ), ), ), )]")
+
+	parser := New(scanner.NewTokenSource(tokens), log, Default)
+	parser.SetConfig(Config{MaxErrors: 2})
+
+	expr := parser.ParseDocument()
+
+	if expr == nil {
+		t.Fatalf("expect parseRecover's bailout to still produce a tree")
+	}
+
+	if _, ok := expr.(*ExprBad); !ok {
+		t.Errorf("expect an *ExprBad once bailout unwinds, got %T", expr)
+	}
+
+	log.Log()
+}
+
+func TestSyncForcesProgressOnRepeatedFailure(t *testing.T) {
+	// An input of nothing but unclosed '{' leaves sync no newline, comma,
+	// or closing delimiter to stop at, so recovery runs out at EOF and the
+	// failures that follow call sync again at that same position; the
+	// parse must still terminate.
+	tokens, log := mustScan(t, "{{{{{{{{{{")
+
+	done := make(chan struct{})
+
+	go func() {
+		New(scanner.NewTokenSource(tokens), log, Default).ParseDocument()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Log()
+	case <-time.After(2 * time.Second):
+		t.Fatalf("ParseDocument did not terminate: sync failed to force progress")
+	}
+}
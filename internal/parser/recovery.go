@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// Config controls the parser's error recovery.
+type Config struct {
+	// MaxErrors is the number of diagnostics the parser will record before
+	// giving up on the rest of the document. Zero means use DefaultConfig's
+	// limit.
+	MaxErrors int
+}
+
+// DefaultConfig is used by New; it matches go/parser's practice of
+// reporting a handful of errors before bailing rather than flooding the
+// caller with cascades.
+var DefaultConfig = Config{MaxErrors: 10}
+
+// SetConfig overrides the error recovery limits. It should be called
+// before ParseDocument.
+func (parser *Parser) SetConfig(config Config) {
+	if config.MaxErrors <= 0 {
+		config.MaxErrors = DefaultConfig.MaxErrors
+	}
+
+	parser.config = config
+}
+
+// exceeded reports whether the parser has already recorded more errors
+// than its configured limit, at which point callers should stop trying to
+// make further progress.
+func (parser *Parser) exceeded() bool {
+	return parser.errorCount > parser.config.MaxErrors
+}
+
+// bailout is panicked by addError once exceeded becomes true, so parsing can
+// unwind straight back out to parseRecover's recover instead of relying on every
+// intervening helper to notice errorCount and unwind cooperatively.
+type bailout struct{}
+
+// sync discards tokens until it reaches a safe restart point: a newline, a
+// close-block, a comma, or a closing delimiter at the current bracket
+// depth (left unconsumed so the caller's own consume call still sees it).
+// It returns an ExprBad spanning the discarded tokens and clears panic
+// mode so the next real error is reported instead of suppressed.
+//
+// sync also guards against a caller looping on it without ever advancing
+// past the position it stopped at (a malformed grammar rule repeatedly
+// failing at the same token): a second call starting from that exact
+// position forces one token of progress before searching for the next
+// boundary, guaranteeing sync can never be the cause of an infinite loop.
+func (parser *Parser) sync() Expr {
+	start := parser.peek().Span
+
+	if start.Start == parser.syncPos {
+		parser.syncCount++
+
+		if parser.syncCount > 1 {
+			parser.advance()
+			parser.syncCount = 0
+		}
+	} else {
+		parser.syncPos = start.Start
+		parser.syncCount = 1
+	}
+
+	depth := 0
+
+	for !parser.isEOF() {
+		switch parser.peek().Kind {
+		case scanner.TokenNewline, scanner.TokenCloseBlock, scanner.TokenComma:
+			if depth == 0 {
+				parser.panicking = false
+				parser.syncPos = parser.peek().Span.Start
+				return &ExprBad{BadSpan: logger.Span{Start: start.Start, End: parser.peek().Span.Start}}
+			}
+
+		case scanner.TokenOpenParen, scanner.TokenOpenBrack, scanner.TokenOpenBrace:
+			depth++
+
+		case scanner.TokenCloseParen, scanner.TokenCloseBrack, scanner.TokenCloseBrace:
+			if depth == 0 {
+				parser.panicking = false
+				parser.syncPos = parser.peek().Span.Start
+				return &ExprBad{BadSpan: logger.Span{Start: start.Start, End: parser.peek().Span.Start}}
+			}
+
+			depth--
+		}
+
+		parser.advance()
+	}
+
+	parser.panicking = false
+	parser.syncPos = parser.peek().Span.Start
+
+	return &ExprBad{BadSpan: logger.Span{Start: start.Start, End: parser.peek().Span.End}}
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+)
+
+// trace prints parser's current token and position under msg, then
+// increases the nesting depth recorded in every subsequent trace line
+// until the matching untrace call. It returns nil when Trace mode is off,
+// so the usual `defer untrace(trace(parser, "parseBlockTable"))` pattern
+// costs one bitmask check when tracing isn't enabled.
+func trace(parser *Parser, msg string) *Parser {
+	if parser.mode&Trace == 0 {
+		return nil
+	}
+
+	parser.printTrace(msg)
+	parser.traceDepth++
+
+	return parser
+}
+
+// untrace is the deferred half of trace. It is safe to call with the nil
+// *Parser that trace returns when tracing is off.
+func untrace(parser *Parser) {
+	if parser == nil {
+		return
+	}
+
+	parser.traceDepth--
+	parser.printTrace("exit")
+}
+
+// printTrace formats a single trace line with logger.DebugStruct, the same
+// debug format the rest of the module uses, and sends it through
+// logger.Logger.Trace, which prints it to standard output.
+func (parser *Parser) printTrace(msg string) {
+	parser.logger.Trace(logger.DebugStruct("Trace", []logger.DebugField{
+		{Key: "Method", Value: msg},
+		{Key: "Depth", Value: fmt.Sprintf("%d", parser.traceDepth)},
+		{Key: "Token", Value: parser.peek().String()},
+		{Key: "Position", Value: fmt.Sprintf("%d", parser.peek().Span.Start)},
+	}))
+}
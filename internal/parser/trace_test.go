@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// captureStdout redirects os.Stdout for the duration of f and returns
+// whatever was written to it, since logger.Logger.Trace prints straight to
+// stdout rather than exposing a sink tests can inject.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+
+	original := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+
+	os.Stdout = w
+	f()
+	w.Close()
+	os.Stdout = original
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestTraceOffProducesNoOutput(t *testing.T) {
+	tokens, log := mustScan(t, "x: 1")
+
+	output := captureStdout(t, func() {
+		New(scanner.NewTokenSource(tokens), log, Default).ParseDocument()
+	})
+
+	if output != "" {
+		t.Errorf("expect no trace output with Trace off, got %q", output)
+	}
+}
+
+func TestTraceModePrintsProductionTrace(t *testing.T) {
+	tokens, log := mustScan(t, "x: 1")
+
+	var output string
+
+	func() {
+		defer func() { log.Log() }()
+
+		output = captureStdout(t, func() {
+			New(scanner.NewTokenSource(tokens), log, Trace).ParseDocument()
+		})
+	}()
+
+	if output == "" {
+		t.Fatalf("expect trace output with Trace on, got none")
+	}
+
+	for _, want := range []string{"parseBlock", "Method", "Depth", "Token", "Position"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expect trace output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(output, "exit") {
+		t.Errorf("expect untrace's \"exit\" entries in the trace output, got:\n%s", output)
+	}
+}
+
+func TestTraceUntraceTracksDepth(t *testing.T) {
+	tokens, log := mustScan(t, "x: 1")
+
+	parser := New(scanner.NewTokenSource(tokens), log, Trace)
+
+	if parser.traceDepth != 0 {
+		t.Fatalf("expect traceDepth to start at 0, got %d", parser.traceDepth)
+	}
+
+	captureStdout(t, func() {
+		outer := trace(parser, "outer")
+		if parser.traceDepth != 1 {
+			t.Errorf("expect traceDepth 1 after one trace call, got %d", parser.traceDepth)
+		}
+
+		inner := trace(parser, "inner")
+		if parser.traceDepth != 2 {
+			t.Errorf("expect traceDepth 2 after a nested trace call, got %d", parser.traceDepth)
+		}
+
+		untrace(inner)
+		if parser.traceDepth != 1 {
+			t.Errorf("expect traceDepth back to 1 after the inner untrace, got %d", parser.traceDepth)
+		}
+
+		untrace(outer)
+		if parser.traceDepth != 0 {
+			t.Errorf("expect traceDepth back to 0 after the outer untrace, got %d", parser.traceDepth)
+		}
+	})
+}
+
+func TestTraceReturnsNilWhenOff(t *testing.T) {
+	tokens, log := mustScan(t, "x: 1")
+
+	parser := New(scanner.NewTokenSource(tokens), log, Default)
+
+	if p := trace(parser, "parseBlock"); p != nil {
+		t.Errorf("expect trace to return nil when Trace mode is off, got %v", p)
+	}
+
+	// untrace(nil) must be a safe no-op, matching the
+	// defer untrace(trace(parser, ...)) pattern used throughout the parser.
+	untrace(nil)
+}
@@ -0,0 +1,102 @@
+package parser
+
+// Visitor is implemented by types that want to traverse an Expr tree. Visit
+// is called with every node encountered by Walk; if it returns a non-nil
+// Visitor w, Walk visits the children of node using w, then calls
+// w.Visit(nil) once those children have all been visited (the same
+// post-order signal go/ast.Walk uses).
+type Visitor interface {
+	Visit(node Expr) (w Visitor)
+}
+
+// Walk traverses an Expr tree in source order, calling v.Visit for node and
+// each of its children. It recurses into the operands of ExprUnary and
+// ExprBinary, each key of ExprRef, each item of ExprArray, and the Key,
+// Parent, and Value of every TableItem in ExprTable.
+func Walk(v Visitor, node Expr) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch expr := node.(type) {
+	case *ExprStr, *ExprBool, *ExprInt, *ExprFloat, *ExprBad, *ExprIdent:
+		// No children.
+
+	case *ExprLet:
+		for _, binding := range expr.Bindings {
+			Walk(v, binding.Value)
+		}
+
+		Walk(v, expr.Body)
+
+	case *ExprFunc:
+		Walk(v, expr.Body)
+
+	case *ExprCall:
+		Walk(v, expr.Callee)
+
+		for _, arg := range expr.Args {
+			Walk(v, arg)
+		}
+
+	case *ExprInterp:
+		for _, part := range expr.Parts {
+			if part.Value != nil {
+				Walk(v, part.Value)
+			}
+		}
+
+	case *ExprRef:
+		for _, key := range expr.Keys {
+			Walk(v, key)
+		}
+
+	case *ExprUnary:
+		Walk(v, expr.Right)
+
+	case *ExprBinary:
+		Walk(v, expr.Left)
+		Walk(v, expr.Right)
+
+	case *ExprArray:
+		for _, item := range expr.Items {
+			Walk(v, item)
+		}
+
+	case *ExprTable:
+		for _, item := range expr.Items {
+			walkTableItem(v, item)
+		}
+	}
+
+	v.Visit(nil)
+}
+
+func walkTableItem(v Visitor, item *TableItem) {
+	Walk(v, item.Key)
+
+	if item.Parent != nil {
+		Walk(v, item.Parent)
+	}
+
+	Walk(v, item.Value)
+}
+
+// inspector adapts a plain function into a Visitor so Inspect can be
+// implemented in terms of Walk.
+type inspector func(Expr) bool
+
+func (f inspector) Visit(node Expr) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses an Expr tree in source order, calling f for node and
+// each of its children. It is a convenience wrapper around Walk for callers
+// that don't need the post-order signal.
+func Inspect(node Expr, f func(Expr) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+func mustParseExpr(t *testing.T, source string) Expr {
+	t.Helper()
+
+	tokens, log := mustScan(t, source)
+
+	expr := New(scanner.NewTokenSource(tokens), log, Default).ParseDocument()
+
+	if log.Log() {
+		t.Fatalf("parse %q: unexpected errors", source)
+	}
+
+	return expr
+}
+
+func TestWalkVisitsEveryNode(t *testing.T) {
+	expr := mustParseExpr(t, "x: 1 + 2\ny: [1, 2, 3]")
+
+	var kinds []string
+
+	Inspect(expr, func(node Expr) bool {
+		if node != nil {
+			kinds = append(kinds, exprKind(node))
+		}
+
+		return true
+	})
+
+	counts := map[string]int{}
+	for _, kind := range kinds {
+		counts[kind]++
+	}
+
+	if counts["*parser.ExprBinary"] != 1 {
+		t.Errorf("expect to visit the binary expr once, got %d", counts["*parser.ExprBinary"])
+	}
+
+	if counts["*parser.ExprInt"] != 5 {
+		t.Errorf("expect to visit all 5 ints (1, 2, 1, 2, 3), got %d", counts["*parser.ExprInt"])
+	}
+
+	if counts["*parser.ExprArray"] != 1 {
+		t.Errorf("expect to visit the array once, got %d", counts["*parser.ExprArray"])
+	}
+}
+
+func TestWalkSendsPostOrderNilSignal(t *testing.T) {
+	expr := mustParseExpr(t, "x: 1 + 2")
+
+	var entries, exits int
+
+	// A Visitor that always keeps going (returns itself on a non-nil node)
+	// gets a paired Visit(nil) for every node it entered; Walk only sends
+	// that post-order signal when entry returned non-nil, so a Visitor
+	// that stops early (like Inspect's f returning false) gets no exit for
+	// the node it stopped at.
+	var self visitFunc
+	self = func(node Expr) Visitor {
+		if node == nil {
+			exits++
+			return nil
+		}
+
+		entries++
+
+		return self
+	}
+
+	Walk(self, expr)
+
+	if entries == 0 {
+		t.Fatalf("expect at least one entry")
+	}
+
+	if entries != exits {
+		t.Errorf("expect every entry to be matched by a nil post-order signal, got %d entries, %d exits", entries, exits)
+	}
+}
+
+func TestInspectStopsDescendingWhenFFalse(t *testing.T) {
+	expr := mustParseExpr(t, "x: 1 + 2")
+
+	visited := 0
+
+	Inspect(expr, func(node Expr) bool {
+		if node == nil {
+			return false
+		}
+
+		visited++
+
+		// Refuse to descend into anything, so only the root is visited.
+		return false
+	})
+
+	if visited != 1 {
+		t.Errorf("expect only the root to be visited, got %d", visited)
+	}
+}
+
+// visitFunc adapts a plain function to Visitor for tests that need access
+// to the return value Walk passes between a node and its post-order signal,
+// unlike Inspect's simpler bool-returning callback.
+type visitFunc func(Expr) Visitor
+
+func (f visitFunc) Visit(node Expr) Visitor {
+	return f(node)
+}
+
+// exprKind returns node's dynamic type name for assertions that just want
+// to count how many of each kind Walk visited.
+func exprKind(node Expr) string {
+	switch node.(type) {
+	case *ExprStr:
+		return "*parser.ExprStr"
+	case *ExprBool:
+		return "*parser.ExprBool"
+	case *ExprInt:
+		return "*parser.ExprInt"
+	case *ExprFloat:
+		return "*parser.ExprFloat"
+	case *ExprIdent:
+		return "*parser.ExprIdent"
+	case *ExprBinary:
+		return "*parser.ExprBinary"
+	case *ExprUnary:
+		return "*parser.ExprUnary"
+	case *ExprArray:
+		return "*parser.ExprArray"
+	case *ExprTable:
+		return "*parser.ExprTable"
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,822 @@
+// Package query evaluates JSONPath-like expressions against the plain
+// any tree produced by evaluator.Evaluator.Evaluate: $ for root, .name or
+// ['name'] for a child, [n] for an index, [start:end:step] for a slice,
+// .. for recursive descent, [*] for a wildcard, and [?(<expr>)] for a
+// filter whose <expr> is parsed with this module's own parser.
+package query
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/parser"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+type segmentKind uint8
+
+const (
+	segChild segmentKind = iota
+	segIndex
+	segSlice
+	segWildcard
+	segRecursive
+	segFilter
+)
+
+type sliceRange struct {
+	start, end, step          int
+	hasStart, hasEnd, hasStep bool
+}
+
+type segment struct {
+	kind   segmentKind
+	name   string
+	index  int
+	slice  sliceRange
+	filter parser.Expr
+}
+
+// Compile parses path into a sequence of segments Run and Iter can apply
+// to a value.
+func Compile(path string) ([]segment, error) {
+	c := &compiler{path: path}
+
+	if !c.consume('$') {
+		return nil, fmt.Errorf("query: path must start with '$'")
+	}
+
+	var segments []segment
+
+	for !c.atEnd() {
+		more, err := c.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, more...)
+	}
+
+	return segments, nil
+}
+
+// Run evaluates path against root and collects every match. It is a thin
+// wrapper around Iter for callers that want every result at once.
+func Run(root any, path []segment) ([]any, error) {
+	iter := NewIter(root, path)
+
+	var results []any
+
+	for {
+		value, ok, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return results, nil
+		}
+
+		results = append(results, value)
+	}
+}
+
+// frame is one unit of pending work for Iter: apply path[index:] to value.
+type frame struct {
+	index int
+	value any
+}
+
+// Iter streams the results of a compiled path one at a time, driven by an
+// explicit stack of frames instead of native recursion. That keeps a
+// recursive descent segment (..), which can fan out over an arbitrarily
+// deep value, from growing the Go call stack, and lets a caller stop
+// after the first few results without paying for the rest.
+type Iter struct {
+	root  any
+	path  []segment
+	stack []frame
+}
+
+// NewIter starts an Iter over root using the already-compiled path.
+func NewIter(root any, path []segment) *Iter {
+	return &Iter{
+		root:  root,
+		path:  path,
+		stack: []frame{{index: 0, value: root}},
+	}
+}
+
+// Next advances the iterator, returning its next match. ok is false once
+// every path has been explored.
+func (iter *Iter) Next() (value any, ok bool, err error) {
+	for len(iter.stack) > 0 {
+		top := iter.stack[len(iter.stack)-1]
+		iter.stack = iter.stack[:len(iter.stack)-1]
+
+		if top.index == len(iter.path) {
+			return top.value, true, nil
+		}
+
+		next, err := applySegment(iter.path[top.index], top.value, iter.root)
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Push in reverse so the next Next() call pops the earliest match
+		// first, keeping results in path order.
+		for i := len(next) - 1; i >= 0; i-- {
+			iter.stack = append(iter.stack, frame{index: top.index + 1, value: next[i]})
+		}
+	}
+
+	return nil, false, nil
+}
+
+// applySegment applies a single segment to value, returning every
+// candidate it produces for the rest of the path. root is threaded through
+// for filter expressions that use an absolute ($) reference.
+func applySegment(seg segment, value, root any) ([]any, error) {
+	switch seg.kind {
+	case segChild:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+
+		child, ok := obj[seg.name]
+		if !ok {
+			return nil, nil
+		}
+
+		return []any{child}, nil
+
+	case segIndex:
+		arr, ok := value.([]any)
+		if !ok {
+			return nil, nil
+		}
+
+		index := seg.index
+		if index < 0 {
+			index += len(arr)
+		}
+
+		if index < 0 || index >= len(arr) {
+			return nil, nil
+		}
+
+		return []any{arr[index]}, nil
+
+	case segSlice:
+		arr, ok := value.([]any)
+		if !ok {
+			return nil, nil
+		}
+
+		return sliceArray(arr, seg.slice), nil
+
+	case segWildcard:
+		switch current := value.(type) {
+		case map[string]any:
+			results := make([]any, 0, len(current))
+
+			for _, child := range current {
+				results = append(results, child)
+			}
+
+			return results, nil
+
+		case []any:
+			return append([]any{}, current...), nil
+
+		default:
+			return nil, nil
+		}
+
+	case segRecursive:
+		return descendants(value), nil
+
+	case segFilter:
+		arr, ok := value.([]any)
+		if !ok {
+			return nil, nil
+		}
+
+		var results []any
+
+		for _, item := range arr {
+			result, err := evalFilter(seg.filter, item, root)
+			if err != nil {
+				return nil, err
+			}
+
+			truthy, ok := result.(bool)
+			if !ok {
+				return nil, &logger.Error{
+					Message: "Filter must evaluate to a boolean.",
+					Span:    seg.filter.Span(),
+				}
+			}
+
+			if truthy {
+				results = append(results, item)
+			}
+		}
+
+		return results, nil
+	}
+
+	return nil, nil
+}
+
+// descendants collects value and every value reachable from it, using an
+// explicit stack rather than recursion for the same reason Iter does.
+// Order follows the stack's LIFO pop order and is not otherwise meaningful,
+// since map iteration order is randomized.
+func descendants(value any) []any {
+	var out []any
+
+	stack := []any{value}
+
+	for len(stack) > 0 {
+		n := len(stack) - 1
+		current := stack[n]
+		stack = stack[:n]
+
+		out = append(out, current)
+
+		switch children := current.(type) {
+		case map[string]any:
+			for _, child := range children {
+				stack = append(stack, child)
+			}
+
+		case []any:
+			for _, child := range children {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	return out
+}
+
+// sliceArray applies a Python-style [start:end:step] slice to arr, with
+// negative indices counting from the end, step defaulting to 1, and start
+// and end defaulting to the bounds needed to walk in that step's direction.
+func sliceArray(arr []any, s sliceRange) []any {
+	step := 1
+	if s.hasStep {
+		step = s.step
+	}
+
+	if step == 0 {
+		return nil
+	}
+
+	length := len(arr)
+	start, end := 0, length
+
+	if step < 0 {
+		start, end = length-1, -1
+	}
+
+	if s.hasStart {
+		start = normalizeIndex(s.start, length)
+	}
+
+	if s.hasEnd {
+		end = normalizeIndex(s.end, length)
+	}
+
+	var results []any
+
+	if step > 0 {
+		for i := start; i < end && i < length; i += step {
+			if i >= 0 {
+				results = append(results, arr[i])
+			}
+		}
+	} else {
+		for i := start; i > end && i >= 0; i += step {
+			if i < length {
+				results = append(results, arr[i])
+			}
+		}
+	}
+
+	return results
+}
+
+func normalizeIndex(index, length int) int {
+	if index < 0 {
+		index += length
+	}
+
+	return index
+}
+
+// compiler is a small hand-rolled lexer/parser over a path string. It does
+// not reuse the scanner package: path syntax (.., [*], slices, quoted
+// names) has little in common with edible's own grammar.
+type compiler struct {
+	path string
+	pos  int
+}
+
+func (c *compiler) atEnd() bool {
+	return c.pos >= len(c.path)
+}
+
+func (c *compiler) peek() byte {
+	if c.atEnd() {
+		return 0
+	}
+
+	return c.path[c.pos]
+}
+
+func (c *compiler) advance() byte {
+	ch := c.path[c.pos]
+	c.pos++
+
+	return ch
+}
+
+func (c *compiler) consume(ch byte) bool {
+	if c.peek() == ch {
+		c.pos++
+		return true
+	}
+
+	return false
+}
+
+// parseSegments parses one path step starting at '.' or '[', returning
+// more than one segment when a recursive descent is chained directly into
+// a selector (`..name`, `..*`, `..[...]`).
+func (c *compiler) parseSegments() ([]segment, error) {
+	switch c.peek() {
+	case '.':
+		c.advance()
+
+		if c.peek() == '.' {
+			c.advance()
+
+			seg := segment{kind: segRecursive}
+
+			if c.atEnd() {
+				return []segment{seg}, nil
+			}
+
+			// A selector chained directly onto '..' (`..name`, `..*`,
+			// `..[...]`) has no dot of its own to feed back into this
+			// same '.' or '[' dispatch, so bracket selectors still go
+			// through parseSegments but a bare name or wildcard is parsed
+			// directly here instead.
+			if c.peek() == '[' {
+				rest, err := c.parseSegments()
+				if err != nil {
+					return nil, err
+				}
+
+				return append([]segment{seg}, rest...), nil
+			}
+
+			name, err := c.parseName()
+			if err != nil {
+				return nil, err
+			}
+
+			if name == "*" {
+				return []segment{seg, {kind: segWildcard}}, nil
+			}
+
+			return []segment{seg, {kind: segChild, name: name}}, nil
+		}
+
+		name, err := c.parseName()
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "*" {
+			return []segment{{kind: segWildcard}}, nil
+		}
+
+		return []segment{{kind: segChild, name: name}}, nil
+
+	case '[':
+		seg, err := c.parseBracket()
+		if err != nil {
+			return nil, err
+		}
+
+		return []segment{seg}, nil
+
+	default:
+		return nil, fmt.Errorf("query: unexpected character %q at position %d", c.peek(), c.pos)
+	}
+}
+
+func (c *compiler) parseName() (string, error) {
+	if c.peek() == '*' {
+		c.advance()
+		return "*", nil
+	}
+
+	start := c.pos
+
+	for !c.atEnd() && isNameByte(c.peek()) {
+		c.advance()
+	}
+
+	if c.pos == start {
+		return "", fmt.Errorf("query: expect name at position %d", start)
+	}
+
+	return c.path[start:c.pos], nil
+}
+
+func isNameByte(ch byte) bool {
+	return ch == '_' ||
+		(ch >= 'a' && ch <= 'z') ||
+		(ch >= 'A' && ch <= 'Z') ||
+		(ch >= '0' && ch <= '9')
+}
+
+func (c *compiler) parseBracket() (segment, error) {
+	// Consume '['.
+	c.advance()
+
+	switch {
+	case c.peek() == '*':
+		c.advance()
+
+		if !c.consume(']') {
+			return segment{}, fmt.Errorf("query: expect ']' at position %d", c.pos)
+		}
+
+		return segment{kind: segWildcard}, nil
+
+	case c.peek() == '?':
+		return c.parseFilter()
+
+	case c.peek() == '\'' || c.peek() == '"':
+		return c.parseQuotedName()
+
+	default:
+		return c.parseIndexOrSlice()
+	}
+}
+
+func (c *compiler) parseFilter() (segment, error) {
+	// Consume '?'.
+	c.advance()
+
+	if !c.consume('(') {
+		return segment{}, fmt.Errorf("query: expect '(' after '?' at position %d", c.pos)
+	}
+
+	start := c.pos
+	depth := 1
+
+	for !c.atEnd() && depth > 0 {
+		switch c.peek() {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+
+		if depth > 0 {
+			c.advance()
+		}
+	}
+
+	if depth != 0 {
+		return segment{}, fmt.Errorf("query: unterminated filter expression")
+	}
+
+	text := c.path[start:c.pos]
+
+	// Consume the filter's own closing ')'.
+	c.advance()
+
+	if !c.consume(']') {
+		return segment{}, fmt.Errorf("query: expect ']' after filter at position %d", c.pos)
+	}
+
+	expr, err := parseFilterExpr(text)
+	if err != nil {
+		return segment{}, err
+	}
+
+	return segment{kind: segFilter, filter: expr}, nil
+}
+
+func (c *compiler) parseQuotedName() (segment, error) {
+	quote := c.advance()
+	start := c.pos
+
+	for !c.atEnd() && c.peek() != quote {
+		c.advance()
+	}
+
+	if c.atEnd() {
+		return segment{}, fmt.Errorf("query: unterminated quoted name")
+	}
+
+	name := c.path[start:c.pos]
+
+	// Consume the closing quote.
+	c.advance()
+
+	if !c.consume(']') {
+		return segment{}, fmt.Errorf("query: expect ']' at position %d", c.pos)
+	}
+
+	return segment{kind: segChild, name: name}, nil
+}
+
+func (c *compiler) parseIndexOrSlice() (segment, error) {
+	first, hasFirst, err := c.parseOptionalInt()
+	if err != nil {
+		return segment{}, err
+	}
+
+	if c.peek() != ':' {
+		if !hasFirst {
+			return segment{}, fmt.Errorf("query: expect index at position %d", c.pos)
+		}
+
+		if !c.consume(']') {
+			return segment{}, fmt.Errorf("query: expect ']' at position %d", c.pos)
+		}
+
+		return segment{kind: segIndex, index: first}, nil
+	}
+
+	// Consume ':'.
+	c.advance()
+
+	second, hasSecond, err := c.parseOptionalInt()
+	if err != nil {
+		return segment{}, err
+	}
+
+	slice := sliceRange{start: first, hasStart: hasFirst, end: second, hasEnd: hasSecond}
+
+	if c.consume(':') {
+		step, hasStep, err := c.parseOptionalInt()
+		if err != nil {
+			return segment{}, err
+		}
+
+		slice.step = step
+		slice.hasStep = hasStep
+	}
+
+	if !c.consume(']') {
+		return segment{}, fmt.Errorf("query: expect ']' at position %d", c.pos)
+	}
+
+	return segment{kind: segSlice, slice: slice}, nil
+}
+
+func (c *compiler) parseOptionalInt() (int, bool, error) {
+	start := c.pos
+	neg := c.peek() == '-'
+
+	if neg {
+		c.advance()
+	}
+
+	digitsStart := c.pos
+
+	for !c.atEnd() && c.peek() >= '0' && c.peek() <= '9' {
+		c.advance()
+	}
+
+	if c.pos == digitsStart {
+		if neg {
+			return 0, false, fmt.Errorf("query: expect digits after '-' at position %d", start)
+		}
+
+		return 0, false, nil
+	}
+
+	value, err := strconv.Atoi(c.path[start:c.pos])
+	if err != nil {
+		return 0, false, err
+	}
+
+	return value, true, nil
+}
+
+// parseFilterExpr parses the inside of a [?( ... )] filter with edible's
+// own scanner and parser, so a filter can use the same literals, refs, and
+// comparison/logic operators as the rest of the language.
+func parseFilterExpr(text string) (parser.Expr, error) {
+	log := logger.New("<filter>", text)
+	tokens := scanner.New(text, log).Scan()
+
+	if log.Log() {
+		return nil, fmt.Errorf("query: invalid filter expression %q", text)
+	}
+
+	expr := parser.ParseExpr(scanner.NewTokenSource(tokens), log, parser.Default)
+
+	if log.Log() {
+		return nil, fmt.Errorf("query: invalid filter expression %q", text)
+	}
+
+	return expr, nil
+}
+
+// evalFilter evaluates a filter expression against candidate (the array
+// element currently under test). root is used for absolute ($) references.
+// Only the subset of the grammar that makes sense without a table to bind
+// against is supported: literals, refs, unary and binary operators.
+func evalFilter(expr parser.Expr, candidate, root any) (any, error) {
+	switch node := expr.(type) {
+	case *parser.ExprInt:
+		return node.Value, nil
+
+	case *parser.ExprFloat:
+		return node.Value, nil
+
+	case *parser.ExprStr:
+		return node.Value, nil
+
+	case *parser.ExprBool:
+		return node.Value, nil
+
+	case *parser.ExprRef:
+		base := candidate
+		if node.Modifier == parser.RefAbsolute {
+			base = root
+		}
+
+		for _, rawKey := range node.Keys {
+			key, err := evalFilter(rawKey, candidate, root)
+			if err != nil {
+				return nil, err
+			}
+
+			next, ok := indexInto(base, key)
+			if !ok {
+				return nil, &logger.Error{
+					Message: "Key not found.",
+					Span:    rawKey.Span(),
+				}
+			}
+
+			base = next
+		}
+
+		return base, nil
+
+	case *parser.ExprUnary:
+		right, err := evalFilter(node.Right, candidate, root)
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := toFloat(right)
+		if !ok {
+			return nil, &logger.Error{
+				Message: "Expect integer or float.",
+				Span:    node.Right.Span(),
+			}
+		}
+
+		if node.Op == parser.UnaryMinus {
+			return -value, nil
+		}
+
+		return value, nil
+
+	case *parser.ExprBinary:
+		left, err := evalFilter(node.Left, candidate, root)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := evalFilter(node.Right, candidate, root)
+		if err != nil {
+			return nil, err
+		}
+
+		return evalBinary(node.Op, left, right, node.Span())
+
+	default:
+		return nil, &logger.Error{
+			Message: "Unsupported expression in filter.",
+			Span:    expr.Span(),
+		}
+	}
+}
+
+func indexInto(base, key any) (any, bool) {
+	switch current := base.(type) {
+	case map[string]any:
+		name, ok := key.(string)
+		if !ok {
+			return nil, false
+		}
+
+		value, ok := current[name]
+
+		return value, ok
+
+	case []any:
+		index, ok := toFloat(key)
+		if !ok {
+			return nil, false
+		}
+
+		i := int(index)
+		if i < 0 || i >= len(current) {
+			return nil, false
+		}
+
+		return current[i], true
+
+	default:
+		return nil, false
+	}
+}
+
+func evalBinary(op parser.BinaryOp, left, right any, span logger.Span) (any, error) {
+	switch op {
+	case parser.BinaryAnd, parser.BinaryOr:
+		leftBool, ok := left.(bool)
+		if !ok {
+			return nil, &logger.Error{Message: "Expect boolean.", Span: span}
+		}
+
+		rightBool, ok := right.(bool)
+		if !ok {
+			return nil, &logger.Error{Message: "Expect boolean.", Span: span}
+		}
+
+		if op == parser.BinaryAnd {
+			return leftBool && rightBool, nil
+		}
+
+		return leftBool || rightBool, nil
+
+	case parser.BinaryEq:
+		return left == right, nil
+
+	case parser.BinaryNeq:
+		return left != right, nil
+	}
+
+	leftNum, ok := toFloat(left)
+	if !ok {
+		return nil, &logger.Error{Message: "Expect integer or float.", Span: span}
+	}
+
+	rightNum, ok := toFloat(right)
+	if !ok {
+		return nil, &logger.Error{Message: "Expect integer or float.", Span: span}
+	}
+
+	switch op {
+	case parser.BinaryPlus:
+		return leftNum + rightNum, nil
+	case parser.BinaryMinus:
+		return leftNum - rightNum, nil
+	case parser.BinaryStar:
+		return leftNum * rightNum, nil
+	case parser.BinarySlash:
+		return leftNum / rightNum, nil
+	case parser.BinaryLt:
+		return leftNum < rightNum, nil
+	case parser.BinaryLte:
+		return leftNum <= rightNum, nil
+	case parser.BinaryGt:
+		return leftNum > rightNum, nil
+	case parser.BinaryGte:
+		return leftNum >= rightNum, nil
+	}
+
+	return nil, &logger.Error{Message: "Unsupported operator in filter.", Span: span}
+}
+
+// toFloat treats int64 and float64 values (the two numeric types resolve
+// ever produces) as interchangeable for arithmetic and comparison.
+func toFloat(value any) (float64, bool) {
+	switch current := value.(type) {
+	case int64:
+		return float64(current), true
+	case float64:
+		return current, true
+	default:
+		return 0, false
+	}
+}
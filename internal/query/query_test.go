@@ -0,0 +1,116 @@
+package query
+
+import "testing"
+
+func mustRun(t *testing.T, root any, path string) []any {
+	t.Helper()
+
+	segments, err := Compile(path)
+	if err != nil {
+		t.Fatalf("compile %q: %v", path, err)
+	}
+
+	results, err := Run(root, segments)
+	if err != nil {
+		t.Fatalf("run %q: %v", path, err)
+	}
+
+	return results
+}
+
+func TestChildSegment(t *testing.T) {
+	root := map[string]any{"name": "gopher"}
+
+	results := mustRun(t, root, "$.name")
+
+	if len(results) != 1 || results[0] != "gopher" {
+		t.Errorf("expect [\"gopher\"], got %v", results)
+	}
+}
+
+func TestIndexSegment(t *testing.T) {
+	root := map[string]any{"items": []any{int64(1), int64(2), int64(3)}}
+
+	results := mustRun(t, root, "$.items[1]")
+
+	if len(results) != 1 || results[0] != int64(2) {
+		t.Errorf("expect [2], got %v", results)
+	}
+
+	results = mustRun(t, root, "$.items[-1]")
+
+	if len(results) != 1 || results[0] != int64(3) {
+		t.Errorf("expect [3] for negative index, got %v", results)
+	}
+}
+
+func TestSliceSegment(t *testing.T) {
+	root := map[string]any{"items": []any{int64(1), int64(2), int64(3), int64(4)}}
+
+	results := mustRun(t, root, "$.items[1:3]")
+
+	if len(results) != 2 || results[0] != int64(2) || results[1] != int64(3) {
+		t.Errorf("expect [2, 3], got %v", results)
+	}
+}
+
+func TestWildcardSegment(t *testing.T) {
+	root := map[string]any{"items": []any{int64(1), int64(2)}}
+
+	results := mustRun(t, root, "$.items[*]")
+
+	if len(results) != 2 {
+		t.Errorf("expect 2 results, got %v", results)
+	}
+}
+
+func TestRecursiveSegment(t *testing.T) {
+	root := map[string]any{
+		"a": map[string]any{"name": "inner"},
+		"name": "outer",
+	}
+
+	results := mustRun(t, root, "$..name")
+
+	if len(results) != 2 {
+		t.Fatalf("expect 2 results, got %v", results)
+	}
+}
+
+func TestFilterSegment(t *testing.T) {
+	root := map[string]any{
+		"items": []any{
+			map[string]any{"n": int64(1)},
+			map[string]any{"n": int64(2)},
+			map[string]any{"n": int64(3)},
+		},
+	}
+
+	results := mustRun(t, root, "$.items[?(.n > 1)]")
+
+	if len(results) != 2 {
+		t.Errorf("expect 2 results, got %v", results)
+	}
+}
+
+func TestIterStopsEarly(t *testing.T) {
+	root := map[string]any{"items": []any{int64(1), int64(2), int64(3)}}
+
+	segments, err := Compile("$.items[*]")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	iter := NewIter(root, segments)
+
+	value, ok, err := iter.Next()
+	if err != nil || !ok {
+		t.Fatalf("expect a first result, got %v, %v, %v", value, ok, err)
+	}
+}
+
+func TestCompileRejectsMissingRoot(t *testing.T) {
+	if _, err := Compile("name"); err == nil {
+		t.Errorf("expect an error for a path missing the leading '$'")
+	}
+}
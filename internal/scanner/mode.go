@@ -0,0 +1,22 @@
+package scanner
+
+// Mode is a bitmask controlling which lexical features the Scanner
+// recognizes, modeled after text/scanner.Mode. Embedders that reuse this
+// lexer for non-edible configs can disable features they don't need instead
+// of forking the scanner.
+type Mode uint16
+
+const (
+	ScanStrings Mode = 1 << iota
+	ScanInts
+	ScanFloats
+	ScanIdents
+	ScanComments
+	SkipComments
+	WhitespaceSensitive
+)
+
+// Default is the Mode used by New, matching edible's own grammar: every
+// literal kind is recognized, comments are scanned and discarded, and
+// indentation is significant.
+const Default Mode = ScanStrings | ScanInts | ScanFloats | ScanIdents | ScanComments | SkipComments | WhitespaceSensitive
@@ -1,6 +1,8 @@
 package scanner
 
 import (
+	"io"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -13,9 +15,19 @@ import (
 // TODO: Floor division operator.
 // TODO: Basic builtin functions and custom functions.
 type Scanner struct {
-	source string
-	logger *logger.Logger
-	tokens Tokens
+	source   string
+	filename string
+	file     *logger.File
+	logger   *logger.Logger
+	mode     Mode
+
+	// queue holds tokens that have been produced by scan() but not yet
+	// claimed by Next(). A single scan() call can emit more than one token
+	// (a dedent closes several blocks at once), so this has to be a queue
+	// rather than a single pending slot.
+	queue    Tokens
+	emitted  int
+	finished bool
 
 	indents     []int
 	sensitivity int
@@ -23,40 +35,104 @@ type Scanner struct {
 
 	start   int
 	current int
-	line    int
 }
 
-func New(source string, logger *logger.Logger) *Scanner {
-	return &Scanner{
-		source: source,
-		logger: logger,
-		tokens: make(Tokens, 0, len(source)/2),
+// New creates a Scanner over source using the Default mode and sharing
+// logger's File, so positions it produces line up with the file the logger
+// reports errors against.
+func New(source string, log *logger.Logger) *Scanner {
+	scanner := new(Scanner)
+	scanner.Init(strings.NewReader(source), "")
+	scanner.file = log.File()
+	scanner.logger = log
 
-		indents:     []int{},
-		sensitivity: 0,
-		isLineStart: false,
+	return scanner
+}
 
-		start:   0,
-		current: 0,
-		line:    1,
+// Init resets the Scanner to read from r, modeled after text/scanner.Init.
+// filename is used only for diagnostics and is recorded in a fresh FileSet
+// of the Scanner's own; callers that want positions to share a FileSet with
+// a Logger should use New instead. Init restores Mode to Default; callers
+// that need a restricted mode should call SetMode afterward.
+func (scanner *Scanner) Init(r io.Reader, filename string) *Scanner {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		contents = nil
 	}
+
+	scanner.source = string(contents)
+	scanner.filename = filename
+	scanner.file = logger.NewFileSet().AddFile(filename, len(scanner.source))
+	scanner.mode = Default
+
+	scanner.queue = scanner.queue[:0]
+	scanner.emitted = 0
+	scanner.finished = false
+
+	scanner.indents = scanner.indents[:0]
+	scanner.sensitivity = 0
+	scanner.isLineStart = false
+
+	scanner.start = 0
+	scanner.current = 0
+
+	return scanner
 }
 
-func (scanner *Scanner) Scan() Tokens {
-	for !scanner.isEOF() {
+// SetMode overrides the lexical features the Scanner recognizes. It must be
+// called before the first call to Next or Scan.
+func (scanner *Scanner) SetMode(mode Mode) {
+	scanner.mode = mode
+}
+
+// Next returns the next Token from the source one at a time, without
+// accumulating the rest of the file. Once the final TokenEOF has been
+// returned, Next keeps returning TokenEOF.
+func (scanner *Scanner) Next() Token {
+	for len(scanner.queue) == 0 {
+		if scanner.finished {
+			return Token{Kind: TokenEOF}
+		}
+
+		if scanner.isEOF() {
+			// Auto-close all blocks by adding a dedent for every indent.
+			for range scanner.indents {
+				scanner.addToken(TokenCloseBlock)
+			}
+			scanner.indents = scanner.indents[:0]
+
+			// Add final EOF token.
+			scanner.addToken(TokenEOF)
+			scanner.finished = true
+
+			continue
+		}
+
 		scanner.start = scanner.current
 		scanner.scan()
 	}
 
-	// Auto-close all blocks by adding a dedent for every indent.
-	for range scanner.indents {
-		scanner.addToken(TokenCloseBlock)
-	}
+	token := scanner.queue[0]
+	scanner.queue = scanner.queue[1:]
+
+	return token
+}
+
+// Scan drains Next until TokenEOF and returns every token produced. It
+// exists for callers that want the whole file at once (debug printing,
+// tests); the parser itself should prefer Next so it never pays for an
+// up-front token slice on large inputs.
+func (scanner *Scanner) Scan() Tokens {
+	tokens := make(Tokens, 0, len(scanner.source)/2)
 
-	// Add final EOF token.
-	scanner.addToken(TokenEOF)
+	for {
+		token := scanner.Next()
+		tokens = append(tokens, token)
 
-	return scanner.tokens
+		if token.Kind == TokenEOF {
+			return tokens
+		}
+	}
 }
 
 func (scanner *Scanner) scan() {
@@ -88,11 +164,53 @@ func (scanner *Scanner) scan() {
 	case '/':
 		scanner.addToken(TokenSlash)
 	case '<':
-		scanner.addToken(TokenLess)
+		if scanner.peek() == '=' {
+			scanner.advance()
+			scanner.addToken(TokenLessEqual)
+		} else {
+			scanner.addToken(TokenLess)
+		}
+	case '>':
+		if scanner.peek() == '=' {
+			scanner.advance()
+			scanner.addToken(TokenGreaterEqual)
+		} else {
+			scanner.addToken(TokenGreater)
+		}
 	case '.':
 		scanner.addToken(TokenDot)
 	case '$':
 		scanner.addToken(TokenDollar)
+	case '=':
+		if scanner.peek() == '=' {
+			scanner.advance()
+			scanner.addToken(TokenEqualEqual)
+		} else {
+			scanner.addToken(TokenEqual)
+		}
+	case '!':
+		if scanner.peek() == '=' {
+			scanner.advance()
+			scanner.addToken(TokenBangEqual)
+		} else {
+			scanner.addError("Unexpected character.")
+		}
+	case '&':
+		if scanner.peek() == '&' {
+			scanner.advance()
+			scanner.addToken(TokenAmpAmp)
+		} else {
+			scanner.addToken(TokenAmp)
+		}
+	case '|':
+		if scanner.peek() == '|' {
+			scanner.advance()
+			scanner.addToken(TokenPipePipe)
+		} else {
+			scanner.addToken(TokenPipe)
+		}
+	case '~':
+		scanner.addToken(TokenTilde)
 
 	// Delimiters
 	case '(':
@@ -116,7 +234,11 @@ func (scanner *Scanner) scan() {
 
 	// Comments
 	case '#':
-		scanner.scanComment()
+		if scanner.mode&ScanComments != 0 {
+			scanner.scanComment()
+		} else {
+			scanner.addError("Unexpected character.")
+		}
 
 	// Whitespace: ' ', '\n', and '\r' ('\t' doesn't count)
 	// Also known as the Morgoth, Sauron, and the Witch-King of Angmar
@@ -155,13 +277,17 @@ func (scanner *Scanner) scan() {
 
 	// Strings
 	case '"':
-		scanner.scanString()
+		if scanner.mode&ScanStrings != 0 {
+			scanner.scanString()
+		} else {
+			scanner.addError("Unexpected character.")
+		}
 
 	default:
-		if isAlphabetic(character) {
+		if isAlphabetic(character) && scanner.mode&ScanIdents != 0 {
 			// Identifiers
 			scanner.scanIdentifier()
-		} else if isDigit(character) {
+		} else if isDigit(character) && scanner.mode&(ScanInts|ScanFloats) != 0 {
 			// Numbers (Integers and Floats)
 			scanner.scanNumber()
 		} else if unicode.IsSpace(character) {
@@ -203,11 +329,33 @@ func (scanner *Scanner) scanDash() {
 	scanner.indents = append(scanner.indents, indent)
 }
 
+// scanComment consumes a comment through the end of its line. The leading
+// '#' must already be consumed by the caller (scan's switch dispatches on
+// it as the current character; scanBlock's loop advances past it itself)
+// so start always lands on '#'. Unless SkipComments is set, it is kept as
+// a TokenComment for the parser to attach to nearby nodes.
 func (scanner *Scanner) scanComment() {
+	start := scanner.current - 1
+
 	for scanner.peek() != '\r' && scanner.peek() != '\n' && !scanner.isEOF() {
-		// Ignore everything until a newline or the end.
 		scanner.advance()
 	}
+
+	if scanner.mode&SkipComments != 0 {
+		return
+	}
+
+	base := scanner.file.Base()
+
+	scanner.queue = append(scanner.queue, Token{
+		Kind:  TokenComment,
+		Value: scanner.source[start:scanner.current],
+		Span: logger.Span{
+			Start: base + logger.Pos(start),
+			End:   base + logger.Pos(scanner.current),
+		},
+	})
+	scanner.emitted++
 }
 
 func (scanner *Scanner) scanBlock(previous rune) {
@@ -250,6 +398,10 @@ loop:
 			newline = true
 			scanner.isLineStart = true
 		case '#':
+			// Consume '#' itself so scanComment sees the same precondition
+			// as its call from scan's own switch: the leading '#' already
+			// advanced past.
+			scanner.advance()
 			scanner.scanComment()
 		default:
 			break loop
@@ -280,7 +432,7 @@ loop:
 
 		// Dedenting always involves a newline
 		scanner.addToken(TokenNewline)
-	} else if newline && len(scanner.tokens) != 0 {
+	} else if newline && scanner.emitted != 0 {
 		// Add a newline if needed unless it is a leading newline
 		scanner.addToken(TokenNewline)
 	}
@@ -341,7 +493,7 @@ func (scanner *Scanner) scanNumber() {
 		scanner.advance()
 	}
 
-	if scanner.peek() == '.' && isDigit(scanner.peekNext()) {
+	if scanner.mode&ScanFloats != 0 && scanner.peek() == '.' && isDigit(scanner.peekNext()) {
 		// Consume decimal point.
 		scanner.advance()
 
@@ -376,7 +528,7 @@ func (scanner *Scanner) advance() rune {
 	scanner.current += width
 
 	if codePoint == '\n' {
-		scanner.line += 1
+		scanner.file.AddLine(scanner.file.Base() + logger.Pos(scanner.current))
 	}
 
 	return codePoint
@@ -407,11 +559,11 @@ func (scanner *Scanner) peekNext() rune {
 }
 
 func (scanner *Scanner) isSensitive() bool {
-	return scanner.sensitivity == 0
+	return scanner.sensitivity == 0 && scanner.mode&WhitespaceSensitive != 0
 }
 
 func (scanner *Scanner) sensitize() {
-	if !scanner.isSensitive() {
+	if scanner.sensitivity > 0 {
 		scanner.sensitivity--
 	}
 }
@@ -420,31 +572,34 @@ func (scanner *Scanner) desensitize() {
 	scanner.sensitivity++
 }
 
-func (scanner *Scanner) createPos() logger.Pos {
-	return logger.Pos{
-		Start: scanner.start,
-		End:   scanner.current,
-		Line:  scanner.line,
+func (scanner *Scanner) createSpan() logger.Span {
+	base := scanner.file.Base()
+
+	return logger.Span{
+		Start: base + logger.Pos(scanner.start),
+		End:   base + logger.Pos(scanner.current),
 	}
 }
 
 func (scanner *Scanner) addToken(kind TokenKind) {
-	scanner.tokens = append(scanner.tokens, Token{
+	scanner.queue = append(scanner.queue, Token{
 		Kind: kind,
-		Pos:  scanner.createPos(),
+		Span: scanner.createSpan(),
 	})
+	scanner.emitted++
 }
 
 func (scanner *Scanner) addLiteralToken(kind TokenKind, lexeme string) {
-	scanner.tokens = append(scanner.tokens, Token{
+	scanner.queue = append(scanner.queue, Token{
 		Kind:  kind,
 		Value: lexeme,
-		Pos:   scanner.createPos(),
+		Span:  scanner.createSpan(),
 	})
+	scanner.emitted++
 }
 
 func (scanner *Scanner) addError(message string) {
-	scanner.logger.Add(message, scanner.createPos())
+	scanner.logger.Add(message, scanner.createSpan())
 }
 
 func isDigit(value rune) bool {
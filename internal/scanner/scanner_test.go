@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+)
+
+func TestNextProducesTokensOneAtATime(t *testing.T) {
+	log := logger.New("<test>", "1 + 2")
+
+	s := New("1 + 2", log)
+
+	var kinds []TokenKind
+	for {
+		token := s.Next()
+		kinds = append(kinds, token.Kind)
+
+		if token.Kind == TokenEOF {
+			break
+		}
+	}
+
+	want := []TokenKind{TokenInt, TokenPlus, TokenInt, TokenEOF}
+
+	if len(kinds) != len(want) {
+		t.Fatalf("expect %v, got %v", want, kinds)
+	}
+
+	for index, kind := range want {
+		if kinds[index] != kind {
+			t.Errorf("expect kinds[%d] = %v, got %v", index, kind, kinds[index])
+		}
+	}
+}
+
+func TestNextKeepsReturningEOF(t *testing.T) {
+	log := logger.New("<test>", "1")
+
+	s := New("1", log)
+
+	for s.Next().Kind != TokenEOF {
+	}
+
+	if kind := s.Next().Kind; kind != TokenEOF {
+		t.Errorf("expect Next to keep returning TokenEOF, got %v", kind)
+	}
+
+	if kind := s.Next().Kind; kind != TokenEOF {
+		t.Errorf("expect a second extra call to also return TokenEOF, got %v", kind)
+	}
+}
+
+func TestInitReadsFromReader(t *testing.T) {
+	s := new(Scanner).Init(strings.NewReader("1 + 2"), "<reader>")
+
+	tokens := s.Scan()
+
+	if len(tokens) != 4 || tokens[len(tokens)-1].Kind != TokenEOF {
+		t.Errorf("expect 4 tokens ending in EOF, got %v", tokens)
+	}
+}
+
+func TestSetModeWithoutCommentsStillScansInts(t *testing.T) {
+	s := new(Scanner).Init(strings.NewReader("1"), "")
+	s.SetMode(Default &^ ScanComments &^ SkipComments)
+
+	// Default mode keeps WhitespaceSensitive on; turning off ScanComments
+	// and SkipComments shouldn't change scanning of a plain int.
+	tokens := s.Scan()
+
+	if len(tokens) != 2 || tokens[0].Kind != TokenInt || tokens[1].Kind != TokenEOF {
+		t.Errorf("expect [int, EOF], got %v", tokens)
+	}
+}
+
+func TestScanMatchesNextDrained(t *testing.T) {
+	log := logger.New("<test>", "x: 1")
+
+	viaScan := New("x: 1", log).Scan()
+
+	s := New("x: 1", log)
+
+	var viaNext Tokens
+	for {
+		token := s.Next()
+		viaNext = append(viaNext, token)
+
+		if token.Kind == TokenEOF {
+			break
+		}
+	}
+
+	if len(viaScan) != len(viaNext) {
+		t.Fatalf("expect Scan and a manual Next loop to agree on token count, got %d vs %d", len(viaScan), len(viaNext))
+	}
+
+	for index := range viaScan {
+		if viaScan[index].Kind != viaNext[index].Kind {
+			t.Errorf("token %d: expect %v, got %v", index, viaScan[index].Kind, viaNext[index].Kind)
+		}
+	}
+}
@@ -26,6 +26,21 @@ const (
 	TokenLess
 	TokenDot
 	TokenDollar
+	TokenEqual
+
+	// Comparison and logic operators
+	TokenEqualEqual
+	TokenBangEqual
+	TokenLessEqual
+	TokenGreater
+	TokenGreaterEqual
+	TokenAmpAmp
+	TokenPipePipe
+
+	// Schema operators
+	TokenAmp
+	TokenPipe
+	TokenTilde
 
 	// Delimiters
 	TokenOpenParen
@@ -45,6 +60,9 @@ const (
 	TokenIdent
 	TokenInt
 	TokenFloat
+
+	// Comments
+	TokenComment
 )
 
 func (kind TokenKind) String() string {
@@ -71,6 +89,28 @@ func (kind TokenKind) String() string {
 		return "Dot"
 	case TokenDollar:
 		return "Dollar"
+	case TokenEqual:
+		return "Equal"
+	case TokenEqualEqual:
+		return "EqualEqual"
+	case TokenBangEqual:
+		return "BangEqual"
+	case TokenLessEqual:
+		return "LessEqual"
+	case TokenGreater:
+		return "Greater"
+	case TokenGreaterEqual:
+		return "GreaterEqual"
+	case TokenAmpAmp:
+		return "AmpAmp"
+	case TokenPipePipe:
+		return "PipePipe"
+	case TokenAmp:
+		return "Amp"
+	case TokenPipe:
+		return "Pipe"
+	case TokenTilde:
+		return "Tilde"
 	case TokenOpenParen:
 		return "OpenParen"
 	case TokenCloseParen:
@@ -97,6 +137,8 @@ func (kind TokenKind) String() string {
 		return "Int"
 	case TokenFloat:
 		return "Float"
+	case TokenComment:
+		return "Comment"
 	default:
 		return "Unknown"
 	}
@@ -105,14 +147,14 @@ func (kind TokenKind) String() string {
 type Token struct {
 	Kind  TokenKind
 	Value string
-	Pos   logger.Pos
+	Span  logger.Span
 }
 
 func (token Token) String() string {
 	switch token.Kind {
 	case TokenStr:
 		return fmt.Sprintf("%s(\"%s\")", token.Kind, token.Value)
-	case TokenIdent, TokenInt, TokenFloat:
+	case TokenIdent, TokenInt, TokenFloat, TokenComment:
 		return fmt.Sprintf("%s(%s)", token.Kind, token.Value)
 	default:
 		return token.Kind.String()
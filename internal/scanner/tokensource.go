@@ -0,0 +1,57 @@
+package scanner
+
+// TokenSource is a pull-based feed of tokens: parser.New consumes one
+// through Next instead of indexing into a materialized Tokens slice, so a
+// very large config can be parsed without holding its whole token stream in
+// memory, and a caller like an LSP can feed tokens in as it scans them.
+type TokenSource interface {
+	// Next returns the next token in the stream. Once the stream is
+	// exhausted, it keeps returning the final token (TokenEOF) rather than
+	// panicking, so a caller that forgets to check for EOF just stalls
+	// instead of crashing.
+	Next() *Token
+}
+
+// sliceSource adapts an already-scanned Tokens slice to TokenSource.
+type sliceSource struct {
+	tokens Tokens
+	pos    int
+}
+
+// NewTokenSource adapts tokens, a slice scanned in full ahead of time, to
+// TokenSource.
+func NewTokenSource(tokens Tokens) TokenSource {
+	return &sliceSource{tokens: tokens}
+}
+
+func (source *sliceSource) Next() *Token {
+	token := &source.tokens[source.pos]
+
+	if source.pos < len(source.tokens)-1 {
+		source.pos++
+	}
+
+	return token
+}
+
+// scannerSource adapts a *Scanner directly to TokenSource, so a parser can
+// pull tokens straight out of the lexer as it needs them instead of going
+// through a materialized Tokens slice at all.
+type scannerSource struct {
+	scanner *Scanner
+	last    Token
+}
+
+// NewScannerSource adapts scanner to TokenSource. Unlike NewTokenSource,
+// nothing is scanned up front: each Next call runs the lexer just far
+// enough to produce one more token, which is what lets a large input be
+// parsed without its whole token stream ever sitting in memory at once.
+func NewScannerSource(scanner *Scanner) TokenSource {
+	return &scannerSource{scanner: scanner}
+}
+
+func (source *scannerSource) Next() *Token {
+	source.last = source.scanner.Next()
+
+	return &source.last
+}
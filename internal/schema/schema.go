@@ -0,0 +1,406 @@
+// Package schema implements CUE-style unification over the same Expr tree
+// internal/parser produces: a schema is an ordinary edible expression whose
+// leaves are type names (int, string, bool, float, null), constraint
+// predicates (>=0, <=100, ~"regex"), and the & (conjunction) and |
+// (disjunction) operators, composed with tables and arrays for shape.
+package schema
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/HereIsKevin/edible/internal/logger"
+	"github.com/HereIsKevin/edible/internal/parser"
+	"github.com/HereIsKevin/edible/internal/scanner"
+)
+
+// Compile parses source as a schema expression, the same way a document's
+// inline expressions are parsed.
+func Compile(source string) (parser.Expr, error) {
+	log := logger.New("<schema>", source)
+	tokens := scanner.New(source, log).Scan()
+
+	if log.Log() {
+		return nil, fmt.Errorf("schema: invalid schema %q", source)
+	}
+
+	expr := parser.ParseExpr(scanner.NewTokenSource(tokens), log, parser.Default)
+
+	if log.Log() {
+		return nil, fmt.Errorf("schema: invalid schema %q", source)
+	}
+
+	return expr, nil
+}
+
+// Unify implements CUE-style unification between a and b: two concrete
+// values unify iff equal; a value unifies with a type or predicate iff it
+// satisfies it; two constraints (& or |) unify to their intersection, with
+// disjunctions distributing over whichever side they appear on. It returns
+// whichever side is the more concrete result, for chaining into further
+// unification.
+func Unify(a, b parser.Expr) (parser.Expr, error) {
+	if disjoin, ok := asDisjoin(a); ok {
+		return unifyDisjoin(disjoin, b)
+	}
+
+	if disjoin, ok := asDisjoin(b); ok {
+		return unifyDisjoin(disjoin, a)
+	}
+
+	if conjoin, ok := asUnify(a); ok {
+		return unifyConjoin(conjoin, b)
+	}
+
+	if conjoin, ok := asUnify(b); ok {
+		return unifyConjoin(conjoin, a)
+	}
+
+	if ident, ok := a.(*parser.ExprIdent); ok && isTypeName(ident.Name) {
+		// Two type names unify to their intersection: themselves if equal,
+		// otherwise nothing satisfies both.
+		if other, ok := b.(*parser.ExprIdent); ok && isTypeName(other.Name) {
+			if ident.Name == other.Name {
+				return a, nil
+			}
+
+			return nil, &logger.Error{
+				Message: fmt.Sprintf("Cannot unify %q with %q.", ident.Name, other.Name),
+				Span:    logger.Span{Start: a.Span().Start, End: b.Span().End},
+			}
+		}
+
+		return b, satisfiesType(b, ident)
+	}
+
+	if ident, ok := b.(*parser.ExprIdent); ok && isTypeName(ident.Name) {
+		return a, satisfiesType(a, ident)
+	}
+
+	if unary, ok := a.(*parser.ExprUnary); ok && isPredicate(unary.Op) {
+		return b, satisfiesPredicate(b, unary)
+	}
+
+	if unary, ok := b.(*parser.ExprUnary); ok && isPredicate(unary.Op) {
+		return a, satisfiesPredicate(a, unary)
+	}
+
+	table, ok := a.(*parser.ExprTable)
+	if other, otherOk := b.(*parser.ExprTable); ok && otherOk {
+		return unifyTables(table, other)
+	}
+
+	array, ok := a.(*parser.ExprArray)
+	if other, otherOk := b.(*parser.ExprArray); ok && otherOk {
+		return unifyArrays(array, other)
+	}
+
+	return unifyScalars(a, b)
+}
+
+func asDisjoin(expr parser.Expr) (*parser.ExprBinary, bool) {
+	binary, ok := expr.(*parser.ExprBinary)
+	return binary, ok && binary.Op == parser.BinaryDisjoin
+}
+
+func asUnify(expr parser.Expr) (*parser.ExprBinary, bool) {
+	binary, ok := expr.(*parser.ExprBinary)
+	return binary, ok && binary.Op == parser.BinaryUnify
+}
+
+func isPredicate(op parser.UnaryOp) bool {
+	return op == parser.UnaryGte || op == parser.UnaryLte || op == parser.UnaryMatch
+}
+
+func isTypeName(name string) bool {
+	switch name {
+	case "int", "float", "string", "bool", "null":
+		return true
+	default:
+		return false
+	}
+}
+
+// unifyDisjoin tries other against each side of disjoin in turn, since a
+// disjunction unifies with something iff at least one of its branches does.
+func unifyDisjoin(disjoin *parser.ExprBinary, other parser.Expr) (parser.Expr, error) {
+	if result, err := Unify(disjoin.Left, other); err == nil {
+		return result, nil
+	}
+
+	if result, err := Unify(disjoin.Right, other); err == nil {
+		return result, nil
+	}
+
+	return nil, &logger.Error{
+		Message: "Value does not satisfy either side of the disjunction.",
+		Span:    disjoin.Span(),
+	}
+}
+
+// unifyConjoin requires other to unify with both sides of conjoin, since a
+// conjunction is the intersection of its branches.
+func unifyConjoin(conjoin *parser.ExprBinary, other parser.Expr) (parser.Expr, error) {
+	left, err := Unify(conjoin.Left, other)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := Unify(conjoin.Right, other)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer whichever side is the concrete value (as opposed to a type
+	// name or predicate), so a chain of unifications narrows toward it.
+	if isConcreteLeaf(left) {
+		return left, nil
+	}
+
+	return right, nil
+}
+
+func isConcreteLeaf(expr parser.Expr) bool {
+	switch expr.(type) {
+	case *parser.ExprStr, *parser.ExprBool, *parser.ExprInt, *parser.ExprFloat:
+		return true
+	default:
+		return false
+	}
+}
+
+// satisfiesType checks that value is a concrete literal of the kind named
+// by ident ("int", "float", "string", "bool", or "null", where null only
+// matches the bare identifier "null" itself, edible's only null-like value).
+func satisfiesType(value parser.Expr, ident *parser.ExprIdent) error {
+	var ok bool
+
+	switch ident.Name {
+	case "int":
+		_, ok = value.(*parser.ExprInt)
+	case "float":
+		_, ok = value.(*parser.ExprFloat)
+	case "string":
+		_, ok = value.(*parser.ExprStr)
+	case "bool":
+		_, ok = value.(*parser.ExprBool)
+	case "null":
+		other, isIdent := value.(*parser.ExprIdent)
+		ok = isIdent && other.Name == "null"
+	}
+
+	if ok {
+		return nil
+	}
+
+	return &logger.Error{
+		Message: fmt.Sprintf("Expect %s.", ident.Name),
+		Span:    value.Span(),
+	}
+}
+
+// satisfiesPredicate checks a >=, <=, or ~ constraint against value.
+func satisfiesPredicate(value parser.Expr, predicate *parser.ExprUnary) error {
+	if predicate.Op == parser.UnaryMatch {
+		return satisfiesMatch(value, predicate)
+	}
+
+	bound, ok := numericValue(predicate.Right)
+	if !ok {
+		return &logger.Error{
+			Message: "Expect integer or float bound.",
+			Span:    predicate.Right.Span(),
+		}
+	}
+
+	actual, ok := numericValue(value)
+	if !ok {
+		return &logger.Error{
+			Message: "Expect integer or float.",
+			Span:    value.Span(),
+		}
+	}
+
+	var satisfied bool
+
+	if predicate.Op == parser.UnaryGte {
+		satisfied = actual >= bound
+	} else {
+		satisfied = actual <= bound
+	}
+
+	if satisfied {
+		return nil
+	}
+
+	op := ">="
+	if predicate.Op == parser.UnaryLte {
+		op = "<="
+	}
+
+	return &logger.Error{
+		Message: fmt.Sprintf("Value does not satisfy %s %g.", op, bound),
+		Span:    value.Span(),
+	}
+}
+
+func satisfiesMatch(value parser.Expr, predicate *parser.ExprUnary) error {
+	str, ok := value.(*parser.ExprStr)
+	if !ok {
+		return &logger.Error{
+			Message: "Expect string.",
+			Span:    value.Span(),
+		}
+	}
+
+	pattern, ok := predicate.Right.(*parser.ExprStr)
+	if !ok {
+		return &logger.Error{
+			Message: "Expect string pattern.",
+			Span:    predicate.Right.Span(),
+		}
+	}
+
+	re, err := regexp.Compile(pattern.Value)
+	if err != nil {
+		return &logger.Error{
+			Message: fmt.Sprintf("Invalid regular expression: %s.", err),
+			Span:    pattern.Span(),
+		}
+	}
+
+	if re.MatchString(str.Value) {
+		return nil
+	}
+
+	return &logger.Error{
+		Message: fmt.Sprintf("Value does not match %q.", pattern.Value),
+		Span:    str.Span(),
+	}
+}
+
+func numericValue(expr parser.Expr) (float64, bool) {
+	switch current := expr.(type) {
+	case *parser.ExprInt:
+		return float64(current.Value), true
+	case *parser.ExprFloat:
+		return current.Value, true
+	default:
+		return 0, false
+	}
+}
+
+// unifyTables requires every field the schema describes to be present in
+// the value and to unify with its field's schema; fields the value has but
+// the schema does not mention are left alone, same as an open CUE struct.
+func unifyTables(value, schema *parser.ExprTable) (parser.Expr, error) {
+	fields := map[string]*parser.TableItem{}
+
+	for _, item := range value.Items {
+		if key, ok := item.Key.(*parser.ExprStr); ok {
+			fields[key.Value] = item
+		}
+	}
+
+	for _, item := range schema.Items {
+		key, ok := item.Key.(*parser.ExprStr)
+		if !ok {
+			continue
+		}
+
+		field, ok := fields[key.Value]
+		if !ok {
+			return nil, &logger.Error{
+				Message: fmt.Sprintf("Missing required field %q.", key.Value),
+				Span:    schema.Span(),
+			}
+		}
+
+		if _, err := Unify(field.Value, item.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// unifyArrays treats a single-item schema array as "every element must
+// satisfy this", and a schema array with one entry per value item as a
+// positional tuple schema.
+func unifyArrays(value, schema *parser.ExprArray) (parser.Expr, error) {
+	if len(schema.Items) == 1 {
+		for _, item := range value.Items {
+			if _, err := Unify(item, schema.Items[0]); err != nil {
+				return nil, err
+			}
+		}
+
+		return value, nil
+	}
+
+	if len(schema.Items) != len(value.Items) {
+		return nil, &logger.Error{
+			Message: fmt.Sprintf("Expect array of length %d.", len(schema.Items)),
+			Span:    value.Span(),
+		}
+	}
+
+	for index, item := range value.Items {
+		if _, err := Unify(item, schema.Items[index]); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// unifyScalars is the base case: two plain literals (or anything else that
+// reached here without matching a constraint form above) unify only if
+// they are the same kind of literal with the same value.
+func unifyScalars(a, b parser.Expr) (parser.Expr, error) {
+	switch left := a.(type) {
+	case *parser.ExprInt:
+		switch right := b.(type) {
+		case *parser.ExprInt:
+			if left.Value == right.Value {
+				return a, nil
+			}
+		case *parser.ExprFloat:
+			if float64(left.Value) == right.Value {
+				return a, nil
+			}
+		}
+
+	case *parser.ExprFloat:
+		switch right := b.(type) {
+		case *parser.ExprInt:
+			if left.Value == float64(right.Value) {
+				return a, nil
+			}
+		case *parser.ExprFloat:
+			if left.Value == right.Value {
+				return a, nil
+			}
+		}
+
+	case *parser.ExprStr:
+		if right, ok := b.(*parser.ExprStr); ok && left.Value == right.Value {
+			return a, nil
+		}
+
+	case *parser.ExprBool:
+		if right, ok := b.(*parser.ExprBool); ok && left.Value == right.Value {
+			return a, nil
+		}
+
+	case *parser.ExprIdent:
+		if right, ok := b.(*parser.ExprIdent); ok && left.Name == right.Name {
+			return a, nil
+		}
+	}
+
+	return nil, &logger.Error{
+		Message: "Values do not unify.",
+		Span:    logger.Span{Start: a.Span().Start, End: b.Span().End},
+	}
+}
@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/HereIsKevin/edible/internal/parser"
+)
+
+// mustCompile parses source as a standalone inline expression, the same way
+// both a value being checked and the schema checking it are parsed.
+func mustCompile(t *testing.T, source string) parser.Expr {
+	t.Helper()
+
+	expr, err := Compile(source)
+	if err != nil {
+		t.Fatalf("compile %q: %v", source, err)
+	}
+
+	return expr
+}
+
+func TestUnifyTypeName(t *testing.T) {
+	value := mustCompile(t, "1")
+	schema := mustCompile(t, "int")
+
+	if _, err := Unify(value, schema); err != nil {
+		t.Errorf("expect 1 to satisfy int, got %v", err)
+	}
+
+	value = mustCompile(t, `"hello"`)
+
+	if _, err := Unify(value, schema); err == nil {
+		t.Errorf("expect a string not to satisfy int")
+	}
+}
+
+func TestUnifyPredicate(t *testing.T) {
+	schema := mustCompile(t, ">=0")
+
+	if _, err := Unify(mustCompile(t, "5"), schema); err != nil {
+		t.Errorf("expect 5 to satisfy >=0, got %v", err)
+	}
+
+	if _, err := Unify(mustCompile(t, "-1"), schema); err == nil {
+		t.Errorf("expect -1 not to satisfy >=0")
+	}
+}
+
+func TestUnifyMatch(t *testing.T) {
+	schema := mustCompile(t, `~"^[a-z]+$"`)
+
+	if _, err := Unify(mustCompile(t, `"gopher"`), schema); err != nil {
+		t.Errorf(`expect "gopher" to match, got %v`, err)
+	}
+
+	if _, err := Unify(mustCompile(t, `"Gopher1"`), schema); err == nil {
+		t.Errorf(`expect "Gopher1" not to match`)
+	}
+}
+
+func TestUnifyConjunction(t *testing.T) {
+	schema := mustCompile(t, ">=0 & <=100")
+
+	if _, err := Unify(mustCompile(t, "50"), schema); err != nil {
+		t.Errorf("expect 50 to satisfy >=0 & <=100, got %v", err)
+	}
+
+	if _, err := Unify(mustCompile(t, "150"), schema); err == nil {
+		t.Errorf("expect 150 not to satisfy >=0 & <=100")
+	}
+}
+
+func TestUnifyDisjunction(t *testing.T) {
+	schema := mustCompile(t, `int | string`)
+
+	if _, err := Unify(mustCompile(t, "1"), schema); err != nil {
+		t.Errorf("expect 1 to satisfy int | string, got %v", err)
+	}
+
+	if _, err := Unify(mustCompile(t, `"hi"`), schema); err != nil {
+		t.Errorf(`expect "hi" to satisfy int | string, got %v`, err)
+	}
+
+	if _, err := Unify(mustCompile(t, "true"), schema); err == nil {
+		t.Errorf("expect true not to satisfy int | string")
+	}
+}
+
+func TestUnifyTableRequiresFields(t *testing.T) {
+	schema := mustCompile(t, "{name: string, age: >=0}")
+
+	if _, err := Unify(mustCompile(t, `{name: "gopher", age: 5}`), schema); err != nil {
+		t.Errorf("expect a matching table to unify, got %v", err)
+	}
+
+	if _, err := Unify(mustCompile(t, `{name: "gopher"}`), schema); err == nil {
+		t.Errorf("expect a table missing a required field to fail")
+	}
+}
+
+func TestUnifyArraySingleSchemaAppliesToEveryItem(t *testing.T) {
+	schema := mustCompile(t, "[int]")
+
+	if _, err := Unify(mustCompile(t, "[1, 2, 3]"), schema); err != nil {
+		t.Errorf("expect every element to satisfy int, got %v", err)
+	}
+
+	if _, err := Unify(mustCompile(t, `[1, "two", 3]`), schema); err == nil {
+		t.Errorf("expect a non-int element to fail")
+	}
+}
+
+func TestUnifyArrayTuple(t *testing.T) {
+	schema := mustCompile(t, `[int, string]`)
+
+	if _, err := Unify(mustCompile(t, `[1, "two"]`), schema); err != nil {
+		t.Errorf("expect a matching tuple to unify, got %v", err)
+	}
+
+	if _, err := Unify(mustCompile(t, `[1, "two", 3]`), schema); err == nil {
+		t.Errorf("expect a wrong-length tuple to fail")
+	}
+}